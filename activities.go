@@ -28,44 +28,47 @@ type ActivitySummary struct {
 	ElapsedTime        int            `json:"elapsed_time"`
 	TotalElevationGain float64        `json:"total_elevation_gain"`
 	Type               ActivityType   `json:"type"`
+	SportType          ActivityType   `json:"sport_type"` // more specific than Type, eg. ActivityTypes.GravelRide
 
 	StartDate      time.Time `json:"start_date"`
 	StartDateLocal time.Time `json:"start_date_local"`
 
-	TimeZone         string   `json:"time_zone"`
-	StartLocation    Location `json:"start_latlng"`
-	EndLocation      Location `json:"end_latlng"`
-	City             string   `json:"location_city"`
-	State            string   `json:"location_state"`
-	Country          string   `json:"location_country"`
-	AchievementCount int      `json:"achievement_count"`
-	KudosCount       int      `json:"kudos_count"`
-	CommentCount     int      `json:"comment_count"`
-	AthleteCount     int      `json:"athlete_count"`
-	PhotoCount       int      `json:"photo_count"`
-	Map              struct {
-		Id              string   `json:"id"`
-		Polyline        Polyline `json:"polyline"`
-		SummaryPolyline Polyline `json:"summary_polyline"`
-	} `json:"map"`
-	Trainer              bool    `json:"trainer"`
-	Commute              bool    `json:"commute"`
-	Manual               bool    `json:"manual"`
-	Private              bool    `json:"private"`
-	Flagged              bool    `json:"flagged"`
-	GearId               string  `json:"gear_id"` // bike or pair of shoes
-	AverageSpeed         float64 `json:"average_speed"`
-	MaximunSpeed         float64 `json:"max_speed"`
-	AverageCadence       float64 `json:"average_cadence"`
-	AverageTemperature   float64 `json:"average_temp"`
-	AveragePower         float64 `json:"average_watts"`
-	WeightedAveragePower int     `json:"weighted_average_watts"`
-	Kilojoules           float64 `json:"kilojoules"`
-	DeviceWatts          bool    `json:"device_watts"`
-	AverageHeartrate     float64 `json:"average_heartrate"`
-	MaximumHeartrate     float64 `json:"max_heartrate"`
-	Truncated            int     `json:"truncated"` // only present if activity is owned by authenticated athlete, returns 0 if not truncated by privacy zones
-	HasKudoed            bool    `json:"has_kudoed"`
+	TimeZone             string      `json:"time_zone"`
+	StartLocation        Location    `json:"start_latlng"`
+	EndLocation          Location    `json:"end_latlng"`
+	City                 string      `json:"location_city"`
+	State                string      `json:"location_state"`
+	Country              string      `json:"location_country"`
+	AchievementCount     int         `json:"achievement_count"`
+	KudosCount           int         `json:"kudos_count"`
+	CommentCount         int         `json:"comment_count"`
+	AthleteCount         int         `json:"athlete_count"`
+	PhotoCount           int         `json:"photo_count"`
+	Map                  PolylineMap `json:"map"`
+	Trainer              bool        `json:"trainer"`
+	Commute              bool        `json:"commute"`
+	Manual               bool        `json:"manual"`
+	Private              bool        `json:"private"`
+	Flagged              bool        `json:"flagged"`
+	GearId               string      `json:"gear_id"` // bike or pair of shoes
+	AverageSpeed         float64     `json:"average_speed"`
+	MaximunSpeed         float64     `json:"max_speed"`
+	AverageCadence       float64     `json:"average_cadence"`
+	AverageTemperature   float64     `json:"average_temp"`
+	AveragePower         float64     `json:"average_watts"`
+	WeightedAveragePower int         `json:"weighted_average_watts"`
+	Kilojoules           float64     `json:"kilojoules"`
+	DeviceWatts          bool        `json:"device_watts"`
+	AverageHeartrate     float64     `json:"average_heartrate"`
+	MaximumHeartrate     float64     `json:"max_heartrate"`
+	Truncated            int         `json:"truncated"` // only present if activity is owned by authenticated athlete, returns 0 if not truncated by privacy zones
+	HasKudoed            bool        `json:"has_kudoed"`
+	SufferScore          float64     `json:"suffer_score"` // relative effort, only present if the athlete has heart rate data
+}
+
+// ActivityMeta is the activity of an effort.
+type ActivityMeta struct {
+	Id int64 `json:"id"`
 }
 
 type BestEffort struct {
@@ -109,11 +112,38 @@ var ActivityTypes = struct {
 	Yoga               ActivityType
 	WinterSport        ActivityType
 	CrossCountrySkiing ActivityType
+
+	// sport types, only in ActivitySummary.SportType
+	MountainBikeRide              ActivityType
+	GravelRide                    ActivityType
+	EMountainBikeRide             ActivityType
+	Velomobile                    ActivityType
+	Handcycle                     ActivityType
+	Wheelchair                    ActivityType
+	TrailRun                      ActivityType
+	VirtualRun                    ActivityType
+	Sail                          ActivityType
+	Skateboard                    ActivityType
+	Golf                          ActivityType
+	Soccer                        ActivityType
+	Tennis                        ActivityType
+	TableTennis                   ActivityType
+	Badminton                     ActivityType
+	Squash                        ActivityType
+	Racquetball                   ActivityType
+	Pickleball                    ActivityType
+	Padel                         ActivityType
+	HighIntensityIntervalTraining ActivityType
+	Pilates                       ActivityType
 }{"Ride", "AlpineSki", "BackcountrySki", "Hike", "IceSkate", "InlineSkate", "NordicSki", "RollerSki",
 	"Run", "Walk", "Workout", "Snowboard", "Snowshoe", "Kitesurf", "Windsurf", "Swim", "VirtualRide", "EBikeRide",
 
 	"WaterSport", "Canoeing", "Kayaking", "Rowing", "StandUpPaddling", "Surfing",
 	"Crossfit", "Elliptical", "RockClimbing", "StairStepper", "WeightTraining", "Yoga", "WinterSport", "CrossCountrySkiing",
+
+	"MountainBikeRide", "GravelRide", "EMountainBikeRide", "Velomobile", "Handcycle", "Wheelchair", "TrailRun", "VirtualRun",
+	"Sail", "Skateboard", "Golf", "Soccer", "Tennis", "TableTennis", "Badminton", "Squash", "Racquetball", "Pickleball", "Padel",
+	"HighIntensityIntervalTraining", "Pilates",
 }
 
 type Location [2]float64
@@ -218,6 +248,12 @@ func (c *ActivitiesPostCall) Distance(distance float64) *ActivitiesPostCall {
 	return c
 }
 
+// SportType sets the more specific sport type, eg. ActivityTypes.TrailRun.
+func (c *ActivitiesPostCall) SportType(sportType ActivityType) *ActivitiesPostCall {
+	c.ops["sport_type"] = string(sportType)
+	return c
+}
+
 func (c *ActivitiesPostCall) Do() (*ActivityDetailed, error) {
 	data, err := c.service.client.run("POST", "/activities", c.ops)
 	if err != nil {
@@ -264,6 +300,12 @@ func (c *ActivitiesPutCall) Type(activityType ActivityType) *ActivitiesPutCall {
 	return c
 }
 
+// SportType sets the more specific sport type, eg. ActivityTypes.TrailRun.
+func (c *ActivitiesPutCall) SportType(sportType ActivityType) *ActivitiesPutCall {
+	c.ops["sport_type"] = string(sportType)
+	return c
+}
+
 func (c *ActivitiesPutCall) Private(isPrivate bool) *ActivitiesPutCall {
 	// must be 0 or 1, or strava will set to public.
 	if isPrivate {
@@ -394,148 +436,6 @@ func (c *ActivitiesListLapsCall) Do() ([]*LapEffortSummary, error) {
 
 /*********************************************************/
 
-func (t ActivityType) Id() int {
-	switch t {
-	case ActivityTypes.Ride:
-		return 1
-	case ActivityTypes.AlpineSki:
-		return 2
-	case ActivityTypes.BackcountrySki:
-		return 3
-	case ActivityTypes.Hike:
-		return 4
-	case ActivityTypes.IceSkate:
-		return 5
-	case ActivityTypes.InlineSkate:
-		return 6
-	case ActivityTypes.NordicSki:
-		return 7
-	case ActivityTypes.RollerSki:
-		return 8
-	case ActivityTypes.Run:
-		return 9
-	case ActivityTypes.Walk:
-		return 10
-	case ActivityTypes.Workout:
-		return 11
-	case ActivityTypes.Snowboard:
-		return 12
-	case ActivityTypes.Snowshoe:
-		return 13
-	case ActivityTypes.Kitesurf:
-		return 14
-	case ActivityTypes.Windsurf:
-		return 15
-	case ActivityTypes.Swim:
-		return 16
-	case ActivityTypes.VirtualRide:
-		return 17
-	case ActivityTypes.EBikeRide:
-		return 18
-	case ActivityTypes.WaterSport:
-		return 20
-	case ActivityTypes.Canoeing:
-		return 21
-	case ActivityTypes.Kayaking:
-		return 22
-	case ActivityTypes.Rowing:
-		return 23
-	case ActivityTypes.StandUpPaddling:
-		return 24
-	case ActivityTypes.Surfing:
-		return 25
-	case ActivityTypes.Crossfit:
-		return 26
-	case ActivityTypes.Elliptical:
-		return 27
-	case ActivityTypes.RockClimbing:
-		return 28
-	case ActivityTypes.StairStepper:
-		return 29
-	case ActivityTypes.WeightTraining:
-		return 30
-	case ActivityTypes.Yoga:
-		return 31
-	case ActivityTypes.WinterSport:
-		return 40
-	case ActivityTypes.CrossCountrySkiing:
-		return 41
-	}
-
-	return 0
-}
-
-func (t ActivityType) String() string {
-	switch t {
-	case ActivityTypes.Ride:
-		return "Ride"
-	case ActivityTypes.AlpineSki:
-		return "Alpine Ski"
-	case ActivityTypes.BackcountrySki:
-		return "Backcountry Ski"
-	case ActivityTypes.Hike:
-		return "Hike"
-	case ActivityTypes.IceSkate:
-		return "Ice Skate"
-	case ActivityTypes.InlineSkate:
-		return "Inline Skate"
-	case ActivityTypes.NordicSki:
-		return "Nordic Ski"
-	case ActivityTypes.RollerSki:
-		return "Roller Ski"
-	case ActivityTypes.Run:
-		return "Run"
-	case ActivityTypes.Walk:
-		return "Walk"
-	case ActivityTypes.Workout:
-		return "Workout"
-	case ActivityTypes.Snowboard:
-		return "Snowboard"
-	case ActivityTypes.Snowshoe:
-		return "Snowshoe"
-	case ActivityTypes.Kitesurf:
-		return "Kitesurf"
-	case ActivityTypes.Windsurf:
-		return "Windsurf"
-	case ActivityTypes.Swim:
-		return "Swim"
-	case ActivityTypes.VirtualRide:
-		return "VirtualRide"
-	case ActivityTypes.EBikeRide:
-		return "EBikeRide"
-	case ActivityTypes.WaterSport:
-		return "WaterSport"
-	case ActivityTypes.Canoeing:
-		return "Canoeing"
-	case ActivityTypes.Kayaking:
-		return "Kayaking"
-	case ActivityTypes.Rowing:
-		return "Rowing"
-	case ActivityTypes.StandUpPaddling:
-		return "StandUpPaddling"
-	case ActivityTypes.Surfing:
-		return "Surfing"
-	case ActivityTypes.Crossfit:
-		return "Crossfit"
-	case ActivityTypes.Elliptical:
-		return "Elliptical"
-	case ActivityTypes.RockClimbing:
-		return "RockClimbing"
-	case ActivityTypes.StairStepper:
-		return "StairStepper"
-	case ActivityTypes.WeightTraining:
-		return "WeightTraining"
-	case ActivityTypes.Yoga:
-		return "Yoga"
-	case ActivityTypes.WinterSport:
-		return "WinterSport"
-	case ActivityTypes.CrossCountrySkiing:
-		return "CrossCountrySkiing"
-	}
-
-	return "Activity"
-}
-
 func (l Location) String() string {
 	return fmt.Sprintf("[%f, %f]", l[0], l[1])
 }
@@ -309,6 +309,14 @@ func TestActivitiesCreate(t *testing.T) {
 	if string(body) != "description=description&elapsed_time=100&name=name&start_date_local=2009-11-10T23%3A00%3A00Z&type=Ride" {
 		t.Errorf("request body incorrect, got %s", body)
 	}
+
+	// parameters3
+	s.Create("name", ActivityTypes.Run, start, 100).SportType(ActivityTypes.TrailRun).Do()
+
+	body, _ = ioutil.ReadAll(transport.request.Body)
+	if string(body) != "elapsed_time=100&name=name&sport_type=TrailRun&start_date_local=2009-11-10T23%3A00%3A00Z&type=Run" {
+		t.Errorf("request body incorrect, got %s", body)
+	}
 }
 
 func TestActivitiesUpdate(t *testing.T) {
@@ -352,6 +360,12 @@ func TestActivitiesUpdate(t *testing.T) {
 		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
 	}
 
+	s.Update(123).SportType(ActivityTypes.GravelRide).Do()
+
+	if transport.request.URL.RawQuery != "sport_type=GravelRide" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+
 	// parameters3
 	s.Update(123).Private(false).Commute(true).Trainer(false).Do()
 
@@ -0,0 +1,243 @@
+package strava
+
+import (
+	"sort"
+	"time"
+)
+
+// An ActivityFilter selects activities to aggregate, eg. CommuteActivities.
+// Activities must match all the filters given.
+type ActivityFilter func(activity *ActivitySummary) bool
+
+// ActivitiesOfType matches activities with a Type or SportType of any of the types.
+func ActivitiesOfType(types ...ActivityType) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		for _, t := range types {
+			if activity.Type == t || activity.SportType == t {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// ActivitiesInCategory matches activities whose sport is in any of the categories, see ActivityType.Category.
+func ActivitiesInCategory(categories ...ActivityCategory) ActivityFilter {
+	return func(activity *ActivitySummary) bool {
+		for _, c := range categories {
+			if activity.Sport().Category() == c {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// CommuteActivities matches activities marked as commutes.
+func CommuteActivities(activity *ActivitySummary) bool {
+	return activity.Commute
+}
+
+// TrainerActivities matches activities done on a trainer or treadmill.
+func TrainerActivities(activity *ActivitySummary) bool {
+	return activity.Trainer
+}
+
+type TotalsPeriod string
+
+var TotalsPeriods = struct {
+	Week  TotalsPeriod // starting on Monday
+	Month TotalsPeriod
+	Year  TotalsPeriod
+	All   TotalsPeriod
+}{"week", "month", "year", "all"}
+
+func (p TotalsPeriod) valid() bool {
+	return p == TotalsPeriods.Week || p == TotalsPeriods.Month || p == TotalsPeriods.Year || p == TotalsPeriods.All
+}
+
+// PeriodTotals are the totals of the activities starting in the period,
+// in the same form as the AthleteStats buckets.
+type PeriodTotals struct {
+	AthleteTotals
+
+	// Start is midnight UTC of the first local day of the period, in the same form as
+	// ActivitySummary.StartDateLocal. The zero time for TotalsPeriods.All.
+	Start time.Time
+}
+
+// An ActivityStreak is consecutive local days with at least one activity.
+type ActivityStreak struct {
+	Start time.Time // midnight UTC of the first local day
+	End   time.Time // midnight UTC of the last local day
+	Days  int
+}
+
+// Totals adds up the activities by period, unlike AthletesService.Stats for any
+// activity type, period, and for commutes or trainer activities.
+// Periods are in order, from the first activity to the last, including periods without activities.
+// Activities are assigned to periods by StartDateLocal. There are no totals for an unknown period.
+func Totals(activities []*ActivitySummary, period TotalsPeriod, filters ...ActivityFilter) []*PeriodTotals {
+	activities = filterActivities(activities, filters)
+
+	totals := make([]*PeriodTotals, 0)
+	if len(activities) == 0 || !period.valid() {
+		return totals
+	}
+
+	byStart := make(map[time.Time]*PeriodTotals)
+	first, last := periodStart(activities[0].StartDateLocal, period), periodStart(activities[0].StartDateLocal, period)
+	for _, a := range activities {
+		start := periodStart(a.StartDateLocal, period)
+		if start.Before(first) {
+			first = start
+		}
+
+		if start.After(last) {
+			last = start
+		}
+
+		t := byStart[start]
+		if t == nil {
+			t = &PeriodTotals{Start: start}
+			byStart[start] = t
+		}
+
+		t.Count++
+		t.Distance += a.Distance
+		t.MovingTime += a.MovingTime
+		t.ElapsedTime += a.ElapsedTime
+		t.ElevationGain += a.TotalElevationGain
+		t.AchievementCount += a.AchievementCount
+	}
+
+	for start := first; !start.After(last); start = nextPeriod(start, period) {
+		t := byStart[start]
+		if t == nil {
+			t = &PeriodTotals{Start: start}
+		}
+
+		totals = append(totals, t)
+		if period == TotalsPeriods.All {
+			break
+		}
+	}
+
+	return totals
+}
+
+// Streaks returns the runs of consecutive local days with activities, longest first.
+// Streaks of the same length are in the order they happened.
+func Streaks(activities []*ActivitySummary, filters ...ActivityFilter) []*ActivityStreak {
+	days := activityDays(filterActivities(activities, filters))
+
+	dates := make([]time.Time, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	streaks := make([]*ActivityStreak, 0)
+	for _, date := range dates {
+		if len(streaks) != 0 {
+			if s := streaks[len(streaks)-1]; s.End.AddDate(0, 0, 1).Equal(date) {
+				s.End = date
+				s.Days++
+				continue
+			}
+		}
+
+		streaks = append(streaks, &ActivityStreak{Start: date, End: date, Days: 1})
+	}
+
+	sort.SliceStable(streaks, func(i, j int) bool { return streaks[i].Days > streaks[j].Days })
+	return streaks
+}
+
+// EddingtonNumber is the largest number E such that on E days the activities covered
+// at least E units of distance. The unit is in meters, eg. 1000 for kilometers
+// or 1609.344 for miles. Distances of activities on the same local day are added.
+func EddingtonNumber(activities []*ActivitySummary, unit float64, filters ...ActivityFilter) int {
+	days := activityDays(filterActivities(activities, filters))
+
+	distances := make([]float64, 0, len(days))
+	for _, d := range days {
+		distances = append(distances, d/unit)
+	}
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(distances)))
+
+	e := 0
+	for i, d := range distances {
+		if d < float64(i+1) {
+			break
+		}
+		e = i + 1
+	}
+
+	return e
+}
+
+/*********************************************************/
+
+func filterActivities(activities []*ActivitySummary, filters []ActivityFilter) []*ActivitySummary {
+	filtered := make([]*ActivitySummary, 0, len(activities))
+
+	for _, a := range activities {
+		matched := true
+		for _, f := range filters {
+			if !f(a) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			filtered = append(filtered, a)
+		}
+	}
+
+	return filtered
+}
+
+// activityDays returns the total distance of each local day with activities.
+func activityDays(activities []*ActivitySummary) map[time.Time]float64 {
+	days := make(map[time.Time]float64)
+	for _, a := range activities {
+		days[localDate(a.StartDateLocal)] += a.Distance
+	}
+
+	return days
+}
+
+func periodStart(t time.Time, period TotalsPeriod) time.Time {
+	date := localDate(t)
+
+	switch period {
+	case TotalsPeriods.Week:
+		// Monday is the first day
+		return date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	case TotalsPeriods.Month:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case TotalsPeriods.Year:
+		return time.Date(date.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	return time.Time{}
+}
+
+func nextPeriod(start time.Time, period TotalsPeriod) time.Time {
+	switch period {
+	case TotalsPeriods.Week:
+		return start.AddDate(0, 0, 7)
+	case TotalsPeriods.Month:
+		return start.AddDate(0, 1, 0)
+	case TotalsPeriods.Year:
+		return start.AddDate(1, 0, 0)
+	}
+
+	return start
+}
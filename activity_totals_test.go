@@ -0,0 +1,113 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTotalsActivities() []*ActivitySummary {
+	day := func(month time.Month, d, hour int) time.Time {
+		return time.Date(2024, month, d, hour, 0, 0, 0, time.UTC)
+	}
+
+	return []*ActivitySummary{
+		{Type: ActivityTypes.Ride, StartDateLocal: day(1, 1, 8), Distance: 30000, MovingTime: 3600, Commute: true}, // Monday
+		{Type: ActivityTypes.Ride, StartDateLocal: day(1, 1, 18), Distance: 2000, MovingTime: 600, Commute: true},  // same day
+		{Type: ActivityTypes.Ride, StartDateLocal: day(1, 2, 7), Distance: 40000, MovingTime: 4000, Trainer: true}, // streak of 3
+		{Type: ActivityTypes.Swim, StartDateLocal: day(1, 3, 7), Distance: 2500, MovingTime: 3000},
+		{Type: ActivityTypes.Hike, StartDateLocal: day(1, 8, 9), Distance: 12000, TotalElevationGain: 800}, // next Monday
+		{Type: ActivityTypes.Ride, StartDateLocal: day(3, 10, 9), Distance: 5000, MovingTime: 900},
+		{Type: ActivityTypes.Ride, StartDateLocal: day(3, 11, 9), Distance: 3000, MovingTime: 600},
+	}
+}
+
+func TestTotals(t *testing.T) {
+	activities := newTestTotalsActivities()
+
+	months := Totals(activities, TotalsPeriods.Month)
+	if len(months) != 3 {
+		t.Fatalf("should include months without activities, got %d", len(months))
+	}
+
+	if months[0].Count != 5 || months[0].Distance != 86500 || months[0].ElevationGain != 800 || months[0].MovingTime != 11200 {
+		t.Errorf("january totals incorrect, got %+v", months[0])
+	}
+
+	if months[1].Count != 0 || !months[1].Start.Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("february totals incorrect, got %+v", months[1])
+	}
+
+	weeks := Totals(activities, TotalsPeriods.Week, ActivitiesOfType(ActivityTypes.Swim, ActivityTypes.Hike))
+	if len(weeks) != 2 || weeks[0].Count != 1 || weeks[1].Count != 1 {
+		t.Fatalf("weeks incorrect, got %d", len(weeks))
+	}
+
+	if !weeks[1].Start.Equal(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weeks should start on monday, got %v", weeks[1].Start)
+	}
+
+	weeks = Totals(activities[5:], TotalsPeriods.Week)
+	if len(weeks) != 2 || !weeks[0].Start.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("sunday should be in the previous week, got %v", weeks[0].Start)
+	}
+
+	commutes := Totals(activities, TotalsPeriods.Year, ActivitiesOfType(ActivityTypes.Ride), CommuteActivities)
+	if len(commutes) != 1 || commutes[0].Count != 2 || commutes[0].Distance != 32000 {
+		t.Errorf("commute totals incorrect, got %+v", commutes[0])
+	}
+
+	trainer := Totals(activities, TotalsPeriods.All, TrainerActivities)
+	if len(trainer) != 1 || trainer[0].Count != 1 || !trainer[0].Start.IsZero() {
+		t.Errorf("trainer totals incorrect, got %+v", trainer[0])
+	}
+
+	if totals := Totals(activities, TotalsPeriods.All, ActivitiesOfType(ActivityTypes.AlpineSki)); len(totals) != 0 {
+		t.Errorf("should have no totals, got %d", len(totals))
+	}
+
+	if totals := Totals(activities, TotalsPeriod("fortnight")); len(totals) != 0 {
+		t.Errorf("should have no totals for an unknown period, got %d", len(totals))
+	}
+}
+
+func TestStreaks(t *testing.T) {
+	streaks := Streaks(newTestTotalsActivities())
+
+	if len(streaks) != 3 {
+		t.Fatalf("incorrect number of streaks, got %d", len(streaks))
+	}
+
+	if streaks[0].Days != 3 || streaks[0].Start.Day() != 1 || streaks[0].End.Day() != 3 {
+		t.Errorf("longest streak incorrect, got %+v", streaks[0])
+	}
+
+	if streaks[1].Days != 2 || streaks[2].Days != 1 || streaks[2].Start.Day() != 8 {
+		t.Errorf("streaks incorrect, got %+v %+v", streaks[1], streaks[2])
+	}
+
+	if streaks := Streaks(newTestTotalsActivities(), ActivitiesOfType(ActivityTypes.Ride)); streaks[0].Days != 2 {
+		t.Errorf("filtered streak incorrect, got %+v", streaks[0])
+	}
+}
+
+func TestEddingtonNumber(t *testing.T) {
+	activities := newTestTotalsActivities()
+
+	// days of 32, 40, 2.5, 12, 5 and 3 km
+	if e := EddingtonNumber(activities, 1000); e != 4 {
+		t.Errorf("eddington number incorrect, got %d", e)
+	}
+
+	// days of 19.9, 24.9, 1.6, 7.5, 3.1 and 1.9 miles
+	if e := EddingtonNumber(activities, 1609.344); e != 3 {
+		t.Errorf("eddington number in miles incorrect, got %d", e)
+	}
+
+	if e := EddingtonNumber(activities, 1000, ActivitiesOfType(ActivityTypes.Ride)); e != 3 {
+		t.Errorf("ride eddington number incorrect, got %d", e)
+	}
+
+	if e := EddingtonNumber(nil, 1000); e != 0 {
+		t.Errorf("eddington number should be 0, got %d", e)
+	}
+}
@@ -0,0 +1,151 @@
+package strava
+
+import (
+	"sync"
+)
+
+type ActivityCategory string
+
+var ActivityCategories = struct {
+	Cycling ActivityCategory
+	Running ActivityCategory // and walking
+	Water   ActivityCategory
+	Winter  ActivityCategory
+	Fitness ActivityCategory
+	Other   ActivityCategory
+}{"cycling", "running", "water", "winter", "fitness", "other"}
+
+// ActivityTypeInfo describes a known activity or sport type.
+type ActivityTypeInfo struct {
+	Type     ActivityType
+	Id       int    // legacy numeric id, 0 for sport types
+	Name     string // for display
+	Category ActivityCategory
+}
+
+var activityTypeRegistry = struct {
+	sync.RWMutex
+	types map[ActivityType]*ActivityTypeInfo
+}{types: make(map[ActivityType]*ActivityTypeInfo)}
+
+func init() {
+	for _, info := range []*ActivityTypeInfo{
+		{ActivityTypes.Ride, 1, "Ride", ActivityCategories.Cycling},
+		{ActivityTypes.AlpineSki, 2, "Alpine Ski", ActivityCategories.Winter},
+		{ActivityTypes.BackcountrySki, 3, "Backcountry Ski", ActivityCategories.Winter},
+		{ActivityTypes.Hike, 4, "Hike", ActivityCategories.Running},
+		{ActivityTypes.IceSkate, 5, "Ice Skate", ActivityCategories.Winter},
+		{ActivityTypes.InlineSkate, 6, "Inline Skate", ActivityCategories.Other},
+		{ActivityTypes.NordicSki, 7, "Nordic Ski", ActivityCategories.Winter},
+		{ActivityTypes.RollerSki, 8, "Roller Ski", ActivityCategories.Other},
+		{ActivityTypes.Run, 9, "Run", ActivityCategories.Running},
+		{ActivityTypes.Walk, 10, "Walk", ActivityCategories.Running},
+		{ActivityTypes.Workout, 11, "Workout", ActivityCategories.Fitness},
+		{ActivityTypes.Snowboard, 12, "Snowboard", ActivityCategories.Winter},
+		{ActivityTypes.Snowshoe, 13, "Snowshoe", ActivityCategories.Winter},
+		{ActivityTypes.Kitesurf, 14, "Kitesurf", ActivityCategories.Water},
+		{ActivityTypes.Windsurf, 15, "Windsurf", ActivityCategories.Water},
+		{ActivityTypes.Swim, 16, "Swim", ActivityCategories.Water},
+		{ActivityTypes.VirtualRide, 17, "VirtualRide", ActivityCategories.Cycling},
+		{ActivityTypes.EBikeRide, 18, "EBikeRide", ActivityCategories.Cycling},
+
+		{ActivityTypes.WaterSport, 20, "WaterSport", ActivityCategories.Water},
+		{ActivityTypes.Canoeing, 21, "Canoeing", ActivityCategories.Water},
+		{ActivityTypes.Kayaking, 22, "Kayaking", ActivityCategories.Water},
+		{ActivityTypes.Rowing, 23, "Rowing", ActivityCategories.Water},
+		{ActivityTypes.StandUpPaddling, 24, "StandUpPaddling", ActivityCategories.Water},
+		{ActivityTypes.Surfing, 25, "Surfing", ActivityCategories.Water},
+		{ActivityTypes.Crossfit, 26, "Crossfit", ActivityCategories.Fitness},
+		{ActivityTypes.Elliptical, 27, "Elliptical", ActivityCategories.Fitness},
+		{ActivityTypes.RockClimbing, 28, "RockClimbing", ActivityCategories.Fitness},
+		{ActivityTypes.StairStepper, 29, "StairStepper", ActivityCategories.Fitness},
+		{ActivityTypes.WeightTraining, 30, "WeightTraining", ActivityCategories.Fitness},
+		{ActivityTypes.Yoga, 31, "Yoga", ActivityCategories.Fitness},
+		{ActivityTypes.WinterSport, 40, "WinterSport", ActivityCategories.Winter},
+		{ActivityTypes.CrossCountrySkiing, 41, "CrossCountrySkiing", ActivityCategories.Winter},
+
+		{ActivityTypes.MountainBikeRide, 0, "Mountain Bike Ride", ActivityCategories.Cycling},
+		{ActivityTypes.GravelRide, 0, "Gravel Ride", ActivityCategories.Cycling},
+		{ActivityTypes.EMountainBikeRide, 0, "E-Mountain Bike Ride", ActivityCategories.Cycling},
+		{ActivityTypes.Velomobile, 0, "Velomobile", ActivityCategories.Cycling},
+		{ActivityTypes.Handcycle, 0, "Handcycle", ActivityCategories.Cycling},
+		{ActivityTypes.Wheelchair, 0, "Wheelchair", ActivityCategories.Running},
+		{ActivityTypes.TrailRun, 0, "Trail Run", ActivityCategories.Running},
+		{ActivityTypes.VirtualRun, 0, "Virtual Run", ActivityCategories.Running},
+		{ActivityTypes.Sail, 0, "Sail", ActivityCategories.Water},
+		{ActivityTypes.Skateboard, 0, "Skateboard", ActivityCategories.Other},
+		{ActivityTypes.Golf, 0, "Golf", ActivityCategories.Other},
+		{ActivityTypes.Soccer, 0, "Football (Soccer)", ActivityCategories.Other},
+		{ActivityTypes.Tennis, 0, "Tennis", ActivityCategories.Other},
+		{ActivityTypes.TableTennis, 0, "Table Tennis", ActivityCategories.Other},
+		{ActivityTypes.Badminton, 0, "Badminton", ActivityCategories.Other},
+		{ActivityTypes.Squash, 0, "Squash", ActivityCategories.Other},
+		{ActivityTypes.Racquetball, 0, "Racquetball", ActivityCategories.Other},
+		{ActivityTypes.Pickleball, 0, "Pickleball", ActivityCategories.Other},
+		{ActivityTypes.Padel, 0, "Padel", ActivityCategories.Other},
+		{ActivityTypes.HighIntensityIntervalTraining, 0, "HIIT", ActivityCategories.Fitness},
+		{ActivityTypes.Pilates, 0, "Pilates", ActivityCategories.Fitness},
+	} {
+		RegisterActivityType(info)
+	}
+}
+
+// RegisterActivityType adds or replaces a type, eg. one Strava added after this package was updated.
+// Unregistered types are still decoded and encoded unchanged, they just have no metadata.
+func RegisterActivityType(info *ActivityTypeInfo) {
+	activityTypeRegistry.Lock()
+	defer activityTypeRegistry.Unlock()
+
+	registered := *info
+	activityTypeRegistry.types[info.Type] = &registered
+}
+
+// Info returns a copy of the registered metadata of the type, or nil if it is not known.
+func (t ActivityType) Info() *ActivityTypeInfo {
+	activityTypeRegistry.RLock()
+	defer activityTypeRegistry.RUnlock()
+
+	info := activityTypeRegistry.types[t]
+	if info == nil {
+		return nil
+	}
+
+	copied := *info
+	return &copied
+}
+
+// Id returns the legacy numeric id of the type, 0 if it doesn't have one.
+func (t ActivityType) Id() int {
+	if info := t.Info(); info != nil {
+		return info.Id
+	}
+
+	return 0
+}
+
+// Category returns ActivityCategories.Other for unknown types.
+func (t ActivityType) Category() ActivityCategory {
+	if info := t.Info(); info != nil {
+		return info.Category
+	}
+
+	return ActivityCategories.Other
+}
+
+// String returns the display name, "Activity" for unknown types.
+func (t ActivityType) String() string {
+	if info := t.Info(); info != nil {
+		return info.Name
+	}
+
+	return "Activity"
+}
+
+// Sport returns SportType, or Type for activities from before sport types.
+func (a *ActivitySummary) Sport() ActivityType {
+	if a.SportType != "" {
+		return a.SportType
+	}
+
+	return a.Type
+}
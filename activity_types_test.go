@@ -0,0 +1,86 @@
+package strava
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestActivityTypeRegistry(t *testing.T) {
+	if c := ActivityTypes.GravelRide.Category(); c != ActivityCategories.Cycling {
+		t.Errorf("category incorrect, got %v", c)
+	}
+
+	if c := ActivityTypes.Swim.Category(); c != ActivityCategories.Water {
+		t.Errorf("category incorrect, got %v", c)
+	}
+
+	if s := ActivityTypes.TrailRun.String(); s != "Trail Run" {
+		t.Errorf("activity type string incorrect, got %v", s)
+	}
+
+	if id := ActivityTypes.Pickleball.Id(); id != 0 {
+		t.Errorf("sport types should have no id, got %v", id)
+	}
+
+	// unknown types
+	unknown := ActivityType("Hurling")
+	if unknown.Info() != nil || unknown.Category() != ActivityCategories.Other || unknown.String() != "Activity" {
+		t.Errorf("unknown type incorrect, got %v", unknown.Info())
+	}
+
+	RegisterActivityType(&ActivityTypeInfo{Type: unknown, Name: "Hurling", Category: ActivityCategories.Other})
+	defer func() {
+		activityTypeRegistry.Lock()
+		delete(activityTypeRegistry.types, unknown)
+		activityTypeRegistry.Unlock()
+	}()
+
+	if unknown.String() != "Hurling" {
+		t.Errorf("registered type incorrect, got %v", unknown.String())
+	}
+
+	// info is a copy
+	unknown.Info().Name = "changed"
+	if unknown.String() != "Hurling" {
+		t.Errorf("registry should not be changed, got %v", unknown.String())
+	}
+}
+
+func TestActivitySummarySportType(t *testing.T) {
+	var activity ActivitySummary
+	err := json.Unmarshal([]byte(`{"type":"Ride","sport_type":"NewBikeThing"}`), &activity)
+	if err != nil {
+		t.Fatalf("json error: %v", err)
+	}
+
+	if activity.Type != ActivityTypes.Ride || activity.Sport() != "NewBikeThing" {
+		t.Errorf("sport type incorrect, got %v", activity.Sport())
+	}
+
+	// unknown values are kept
+	data, _ := json.Marshal(&activity)
+
+	var decoded ActivitySummary
+	json.Unmarshal(data, &decoded)
+	if decoded.SportType != "NewBikeThing" {
+		t.Errorf("sport type not preserved, got %v", decoded.SportType)
+	}
+
+	activity.SportType = ""
+	if activity.Sport() != ActivityTypes.Ride {
+		t.Errorf("sport should fall back to type, got %v", activity.Sport())
+	}
+
+	filter := ActivitiesInCategory(ActivityCategories.Cycling)
+	if !filter(&ActivitySummary{Type: ActivityTypes.Ride, SportType: ActivityTypes.MountainBikeRide}) {
+		t.Error("should match the category")
+	}
+
+	if filter(&ActivitySummary{Type: ActivityTypes.Run, SportType: ActivityTypes.TrailRun}) {
+		t.Error("should not match the category")
+	}
+
+	if !ActivitiesOfType(ActivityTypes.GravelRide)(&ActivitySummary{Type: ActivityTypes.Ride, SportType: ActivityTypes.GravelRide}) {
+		t.Error("should match the sport type")
+	}
+}
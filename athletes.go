@@ -74,6 +74,12 @@ var Genders = struct {
 	Female      Gender
 }{"", "M", "F"}
 
+// MeasurementPreferences are the values of AthleteDetailed.MeasurementPreference.
+var MeasurementPreferences = struct {
+	Feet   string
+	Meters string
+}{"feet", "meters"}
+
 type AthletesService struct {
 	client *Client
 }
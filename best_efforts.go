@@ -0,0 +1,253 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BestEffortsByDistance finds the fastest portion of the activity covering each of the
+// given distances in meters, eg. 400, 5000 or 42195. Results are in the same order as
+// the distances and are nil if the activity is shorter than the distance.
+// Efforts start and end on stream samples, Distance is set to the requested distance
+// and ElapsedTime is the time taken to cover at least that far.
+func (s *StreamSet) BestEffortsByDistance(distances ...float64) ([]*BestEffort, error) {
+	samples, values, times, err := s.effortSeries()
+	if err != nil {
+		return nil, err
+	}
+
+	efforts := make([]*BestEffort, len(distances))
+	for k, distance := range distances {
+		if distance <= 0 {
+			return nil, errors.New("effort distance must be positive")
+		}
+
+		// sliding window, for every end find the latest start still covering the distance
+		best, bestStart, bestEnd := -1.0, 0, 0
+		start := 0
+		for end := 1; end < len(samples); end++ {
+			if values[end]-values[start] < distance {
+				continue
+			}
+
+			for start+1 < end && values[end]-values[start+1] >= distance {
+				start++
+			}
+
+			if elapsed := times[end] - times[start]; best < 0 || elapsed < best {
+				best, bestStart, bestEnd = elapsed, samples[start], samples[end]
+			}
+		}
+
+		if best < 0 {
+			continue
+		}
+
+		efforts[k] = s.bestEffort(effortDistanceName(distance), bestStart, bestEnd)
+		efforts[k].Distance = distance
+	}
+
+	return efforts, nil
+}
+
+// BestEffortsByDuration finds the portion of the activity covering the greatest distance
+// in each of the given durations in seconds, eg. 60, 1200 or 3600. Results are in the same
+// order as the durations and are nil if the activity is shorter than the duration.
+func (s *StreamSet) BestEffortsByDuration(durations ...int) ([]*BestEffort, error) {
+	samples, values, times, err := s.effortSeries()
+	if err != nil {
+		return nil, err
+	}
+
+	efforts := make([]*BestEffort, len(durations))
+	for k, duration := range durations {
+		if duration <= 0 {
+			return nil, errors.New("effort duration must be positive")
+		}
+
+		best, bestStart, bestEnd := -1.0, 0, 0
+		start := 0
+		for end := 1; end < len(samples); end++ {
+			if times[end]-times[start] < float64(duration) {
+				continue
+			}
+
+			for start+1 < end && times[end]-times[start+1] >= float64(duration) {
+				start++
+			}
+
+			if distance := values[end] - values[start]; distance > best {
+				best, bestStart, bestEnd = distance, samples[start], samples[end]
+			}
+		}
+
+		if best < 0 {
+			continue
+		}
+
+		efforts[k] = s.bestEffort(effortDurationName(duration), bestStart, bestEnd)
+		efforts[k].Distance = best
+	}
+
+	return efforts, nil
+}
+
+// effortSeries returns the valid time samples with the cumulative distance and time at each.
+func (s *StreamSet) effortSeries() ([]int, []float64, []float64, error) {
+	if s.Time == nil || len(s.Time.Data) == 0 {
+		return nil, nil, nil, errors.New("time stream required")
+	}
+
+	if s.Location == nil && s.Distance == nil {
+		return nil, nil, nil, errors.New("location or distance stream required")
+	}
+
+	samples := s.validTimeIndexes()
+	return samples, s.cumulativeDistances(samples), s.elapsedTimes(samples), nil
+}
+
+func (s *StreamSet) bestEffort(name string, start, end int) *BestEffort {
+	summary, _ := s.slice(start, end).Summary()
+
+	effort := &BestEffort{}
+	effort.Name = name
+	effort.ElapsedTime = summary.ElapsedTime
+	effort.MovingTime = summary.MovingTime
+	effort.StartIndex = start
+	effort.EndIndex = end
+
+	return effort
+}
+
+func effortDistanceName(meters float64) string {
+	if meters < 1000 {
+		return fmt.Sprintf("%gm", meters)
+	}
+
+	return fmt.Sprintf("%gk", meters/1000)
+}
+
+func effortDurationName(seconds int) string {
+	name := ""
+	if h := seconds / 3600; h > 0 {
+		name += fmt.Sprintf("%dh", h)
+	}
+
+	if m := seconds % 3600 / 60; m > 0 {
+		name += fmt.Sprintf("%dm", m)
+	}
+
+	if s := seconds % 60; s > 0 {
+		name += fmt.Sprintf("%ds", s)
+	}
+
+	return name
+}
+
+/*********************************************************/
+
+// BestEffortTable ranks best efforts across many activities to build
+// personal record tables. Efforts are grouped by Name and ranked by average speed,
+// so both distance and duration based efforts can be kept in the same table.
+type BestEffortTable struct {
+	size    int
+	efforts map[string][]*BestEffort
+}
+
+// NewBestEffortTable creates a table that keeps the top size efforts for each name.
+// A size below 0 is treated as 0, keeping none.
+func NewBestEffortTable(size int) *BestEffortTable {
+	if size < 0 {
+		size = 0
+	}
+
+	return &BestEffortTable{
+		size:    size,
+		efforts: make(map[string][]*BestEffort),
+	}
+}
+
+// AddActivity records the efforts found in the streams of the given activity.
+// Each effort's Activity and Athlete are set from the activity, and its start dates
+// from the activity's plus the Time sample at its StartIndex. Returns the efforts that
+// placed in the table with their PRRank set, 1 being the fastest.
+func (t *BestEffortTable) AddActivity(activity *ActivitySummary, streams *StreamSet, efforts []*BestEffort) []*BestEffort {
+	for _, e := range efforts {
+		if e == nil {
+			continue
+		}
+
+		var offset time.Duration
+		if streams != nil && streams.Time != nil && streams.Time.valid(e.StartIndex) {
+			offset = time.Duration(streams.Time.Data[e.StartIndex]) * time.Second
+		}
+
+		e.Activity.Id = activity.Id
+		e.Athlete.Id = activity.Athlete.Id
+		e.StartDate = activity.StartDate.Add(offset)
+		e.StartDateLocal = activity.StartDateLocal.Add(offset)
+	}
+
+	return t.Add(efforts...)
+}
+
+// Add records the given efforts. Nil efforts are ignored.
+// Returns the efforts that placed in the table with their PRRank set, 1 being the fastest.
+func (t *BestEffortTable) Add(efforts ...*BestEffort) []*BestEffort {
+	placed := make([]*BestEffort, 0)
+	for _, e := range efforts {
+		if e == nil || e.ElapsedTime <= 0 {
+			continue
+		}
+
+		list := append(t.efforts[e.Name], e)
+		sort.SliceStable(list, func(i, j int) bool {
+			return effortSpeed(list[i]) > effortSpeed(list[j])
+		})
+
+		if len(list) > t.size {
+			for _, dropped := range list[t.size:] {
+				dropped.PRRank = 0
+			}
+			list = list[:t.size]
+		}
+
+		for i, ranked := range list {
+			ranked.PRRank = i + 1
+		}
+		t.efforts[e.Name] = list
+
+		for _, ranked := range list {
+			if ranked == e {
+				placed = append(placed, e)
+			}
+		}
+	}
+
+	return placed
+}
+
+// Get returns the ranked efforts with the given name, fastest first.
+func (t *BestEffortTable) Get(name string) []*BestEffort {
+	list := make([]*BestEffort, len(t.efforts[name]))
+	copy(list, t.efforts[name])
+
+	return list
+}
+
+// Names returns the effort names in the table, sorted.
+func (t *BestEffortTable) Names() []string {
+	names := make([]string, 0, len(t.efforts))
+	for name := range t.efforts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func effortSpeed(e *BestEffort) float64 {
+	return e.Distance / float64(e.ElapsedTime)
+}
@@ -0,0 +1,159 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestEffortStreamSet() *StreamSet {
+	// 10 minutes at 3 m/s, 2 minutes at 5 m/s, 10 minutes at 3 m/s
+	set := &StreamSet{
+		Time:     &IntegerStream{Data: make([]int, 0)},
+		Distance: &DecimalStream{Data: make([]float64, 0)},
+	}
+
+	distance := 0.0
+	for i := 0; i <= 1320; i++ {
+		set.Time.Data = append(set.Time.Data, i)
+		set.Distance.Data = append(set.Distance.Data, distance)
+
+		if i >= 600 && i < 720 {
+			distance += 5
+		} else {
+			distance += 3
+		}
+	}
+
+	return set
+}
+
+func TestStreamSetBestEffortsByDistance(t *testing.T) {
+	set := newTestEffortStreamSet()
+
+	efforts, err := set.BestEffortsByDistance(400, 1000, 100000)
+	if err != nil {
+		t.Fatalf("efforts error: %v", err)
+	}
+
+	if len(efforts) != 3 {
+		t.Fatalf("incorrect number of efforts, got %d", len(efforts))
+	}
+
+	if e := efforts[0]; e.Name != "400m" || e.ElapsedTime != 80 || e.Distance != 400 {
+		t.Errorf("400m effort incorrect, got %v", e.EffortSummary)
+	}
+
+	if e := efforts[0]; e.StartIndex < 600 || e.EndIndex > 720 || e.EndIndex-e.StartIndex != 80 {
+		t.Errorf("400m effort indexes incorrect, got %d %d", e.StartIndex, e.EndIndex)
+	}
+
+	// 600 meters at 5 m/s and 400 meters at 3 m/s
+	if e := efforts[1]; e.Name != "1k" || e.ElapsedTime != 254 {
+		t.Errorf("1k effort incorrect, got %v", e.EffortSummary)
+	}
+
+	if efforts[2] != nil {
+		t.Errorf("effort longer than the activity should be nil, got %v", efforts[2])
+	}
+
+	if _, err := set.BestEffortsByDistance(0); err == nil {
+		t.Error("should return error for invalid distance")
+	}
+
+	if _, err := (&StreamSet{Time: set.Time}).BestEffortsByDistance(400); err == nil {
+		t.Error("should return error if distance stream missing")
+	}
+}
+
+func TestStreamSetBestEffortsByDuration(t *testing.T) {
+	set := newTestEffortStreamSet()
+
+	efforts, err := set.BestEffortsByDuration(60, 180, 3600)
+	if err != nil {
+		t.Fatalf("efforts error: %v", err)
+	}
+
+	if e := efforts[0]; e.Name != "1m" || e.Distance != 300 || e.ElapsedTime != 60 {
+		t.Errorf("1m effort incorrect, got %v", e.EffortSummary)
+	}
+
+	if e := efforts[1]; e.Name != "3m" || e.Distance != 780 {
+		t.Errorf("3m effort incorrect, got %v", e.EffortSummary)
+	}
+
+	if efforts[2] != nil {
+		t.Errorf("effort longer than the activity should be nil, got %v", efforts[2])
+	}
+
+	if n := effortDurationName(5430); n != "1h30m30s" {
+		t.Errorf("duration name incorrect, got %v", n)
+	}
+}
+
+func TestBestEffortTable(t *testing.T) {
+	table := NewBestEffortTable(2)
+
+	slow := &ActivitySummary{Id: 1}
+	slow.Athlete.Id = 123
+	slow.StartDate = time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC)
+
+	fast := &ActivitySummary{Id: 2}
+	fast.Athlete.Id = 123
+
+	set := newTestEffortStreamSet()
+	efforts, _ := set.BestEffortsByDistance(400)
+	efforts[0].ElapsedTime = 100
+
+	slowest := efforts[0]
+	if placed := table.AddActivity(slow, set, efforts); len(placed) != 1 || placed[0].PRRank != 1 {
+		t.Errorf("first effort should place first, got %v", placed)
+	}
+
+	if expected := slow.StartDate.Add(time.Duration(slowest.StartIndex) * time.Second); !slowest.StartDate.Equal(expected) {
+		t.Errorf("effort start date should be its own, got %v", slowest.StartDate)
+	}
+
+	efforts, _ = set.BestEffortsByDistance(400)
+	if placed := table.AddActivity(fast, set, efforts); len(placed) != 1 || placed[0].PRRank != 1 {
+		t.Errorf("faster effort should place first, got %v", placed)
+	}
+
+	efforts, _ = set.BestEffortsByDistance(400)
+	efforts[0].ElapsedTime = 90
+	if placed := table.Add(efforts...); len(placed) != 1 || placed[0].PRRank != 2 {
+		t.Errorf("effort should place second, got %v", placed)
+	}
+
+	if slowest.PRRank != 0 {
+		t.Errorf("effort pushed out of the table should have no rank, got %d", slowest.PRRank)
+	}
+
+	efforts, _ = set.BestEffortsByDistance(400)
+	efforts[0].ElapsedTime = 200
+	if placed := table.Add(efforts...); len(placed) != 0 || efforts[0].PRRank != 0 {
+		t.Errorf("slowest effort should not place, got %v", placed)
+	}
+
+	ranked := table.Get("400m")
+	if len(ranked) != 2 {
+		t.Fatalf("table should keep 2 efforts, got %d", len(ranked))
+	}
+
+	if ranked[0].Activity.Id != 2 || ranked[0].PRRank != 1 || ranked[1].ElapsedTime != 90 || ranked[1].PRRank != 2 {
+		t.Errorf("table ranking incorrect, got %v %v", ranked[0].EffortSummary, ranked[1].EffortSummary)
+	}
+
+	if ranked[0].Athlete.Id != 123 {
+		t.Errorf("athlete not set, got %v", ranked[0].Athlete.Id)
+	}
+
+	if names := table.Names(); len(names) != 1 || names[0] != "400m" {
+		t.Errorf("table names incorrect, got %v", names)
+	}
+
+	// negative sizes keep nothing
+	efforts, _ = set.BestEffortsByDistance(400)
+	if placed := NewBestEffortTable(-1).Add(efforts...); len(placed) != 0 {
+		t.Errorf("negative size table should keep nothing, got %v", placed)
+	}
+}
@@ -0,0 +1,211 @@
+package strava
+
+import (
+	"errors"
+	"math"
+)
+
+// A Climb is a section of a StreamSet with sustained elevation gain.
+type Climb struct {
+	StartIndex    int           `json:"start_index"`
+	EndIndex      int           `json:"end_index"`
+	Distance      float64       `json:"distance"`       // meters
+	ElevationGain float64       `json:"elevation_gain"` // meters, from the start to the top
+	AverageGrade  float64       `json:"average_grade"`  // percent
+	MaximumGrade  float64       `json:"maximum_grade"`  // percent
+	VAM           float64       `json:"vam"`            // vertical meters per hour, 0 if there is no Time stream
+	ClimbCategory ClimbCategory `json:"climb_category"`
+}
+
+// ClimbDetectionOptions control which sections of a StreamSet are considered climbs.
+type ClimbDetectionOptions struct {
+	MinimumGain  float64 // meters
+	MinimumGrade float64 // percent
+
+	// A climb continues through dips in elevation up to this many meters.
+	MaximumDip float64
+
+	// Maximum grade is measured over at least this many meters
+	// if the Grade stream is not available.
+	GradeDistance float64
+}
+
+// DefaultClimbDetectionOptions are used by Climbs if no options are provided.
+var DefaultClimbDetectionOptions = ClimbDetectionOptions{
+	MinimumGain:   20,
+	MinimumGrade:  3,
+	MaximumDip:    10,
+	GradeDistance: 100,
+}
+
+// Climbs detects the climbs in the Elevation and Distance streams, which are required.
+// If the Time stream is available the VAM of each climb is also computed.
+// A climb starts where its average grade to the top first meets MinimumGrade,
+// so a long false flat before it doesn't dilute it.
+func (s *StreamSet) Climbs(options ...ClimbDetectionOptions) ([]*Climb, error) {
+	opts := DefaultClimbDetectionOptions
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	if s.Elevation == nil || s.Distance == nil || len(s.Elevation.Data) != len(s.Distance.Data) {
+		return nil, errors.New("elevation and distance streams required")
+	}
+
+	samples := make([]int, 0, len(s.Elevation.Data))
+	for i := range s.Elevation.Data {
+		if s.Elevation.valid(i) && s.Distance.valid(i) {
+			samples = append(samples, i)
+		}
+	}
+
+	climbs := make([]*Climb, 0)
+	if len(samples) == 0 {
+		return climbs, nil
+	}
+
+	elevation := s.Elevation.Data
+
+	finish := func(low, top int) {
+		if c := s.climb(s.climbStart(low, top, opts.MinimumGrade), top, opts); c != nil {
+			climbs = append(climbs, c)
+		}
+	}
+
+	low, top := samples[0], -1
+	for _, i := range samples[1:] {
+		if top < 0 {
+			// looking for the bottom of the next climb
+			if elevation[i] <= elevation[low] {
+				low = i
+			} else {
+				top = i
+			}
+			continue
+		}
+
+		if elevation[i] < elevation[low] {
+			// fell below where the climb started, so it is over even if the dip is allowed
+			finish(low, top)
+			low, top = i, -1
+		} else if elevation[i] > elevation[top] {
+			top = i
+		} else if elevation[top]-elevation[i] > opts.MaximumDip {
+			finish(low, top)
+			low, top = i, -1
+		}
+	}
+
+	if top >= 0 {
+		finish(low, top)
+	}
+
+	return climbs, nil
+}
+
+// climbStart moves the start of a climb forward past any false flat leading into it,
+// to the first sample where the grade from there to the top is at least minimumGrade.
+func (s *StreamSet) climbStart(start, top int, minimumGrade float64) int {
+	for i := start; i < top; i++ {
+		if !s.Elevation.valid(i) || !s.Distance.valid(i) {
+			continue
+		}
+
+		distance := s.Distance.Data[top] - s.Distance.Data[i]
+		if distance > 0 && (s.Elevation.Data[top]-s.Elevation.Data[i])/distance*100 >= minimumGrade {
+			return i
+		}
+	}
+
+	return start
+}
+
+// climb returns the climb between start and end if it passes the detection options.
+func (s *StreamSet) climb(start, end int, opts ClimbDetectionOptions) *Climb {
+	c := &Climb{
+		StartIndex:    start,
+		EndIndex:      end,
+		Distance:      s.Distance.Data[end] - s.Distance.Data[start],
+		ElevationGain: s.Elevation.Data[end] - s.Elevation.Data[start],
+	}
+
+	if c.Distance <= 0 || c.ElevationGain < opts.MinimumGain {
+		return nil
+	}
+
+	c.AverageGrade = c.ElevationGain / c.Distance * 100
+	if c.AverageGrade < opts.MinimumGrade {
+		return nil
+	}
+
+	c.MaximumGrade = s.maximumGrade(start, end, opts.GradeDistance)
+	c.ClimbCategory = ClimbCategoryFor(c.Distance, c.AverageGrade)
+
+	if s.Time != nil && s.Time.valid(start) && s.Time.valid(end) {
+		if dt := s.Time.Data[end] - s.Time.Data[start]; dt > 0 {
+			c.VAM = c.ElevationGain / float64(dt) * 3600
+		}
+	}
+
+	return c
+}
+
+// maximumGrade returns the steepest grade between start and end, using the Grade stream
+// if available, otherwise measured over windows at least window meters long.
+func (s *StreamSet) maximumGrade(start, end int, window float64) float64 {
+	max := math.Inf(-1)
+
+	if s.Grade != nil && len(s.Grade.Data) == len(s.Elevation.Data) {
+		for i := start; i <= end; i++ {
+			if s.Grade.valid(i) {
+				max = math.Max(max, s.Grade.Data[i])
+			}
+		}
+	} else {
+		j := start
+		for i := start; i <= end; i++ {
+			if !s.Elevation.valid(i) || !s.Distance.valid(i) {
+				continue
+			}
+
+			for j <= end && (j <= i || !s.Elevation.valid(j) || !s.Distance.valid(j) ||
+				s.Distance.Data[j]-s.Distance.Data[i] < window) {
+				j++
+			}
+
+			if j > end {
+				break
+			}
+
+			grade := (s.Elevation.Data[j] - s.Elevation.Data[i]) / (s.Distance.Data[j] - s.Distance.Data[i]) * 100
+			max = math.Max(max, grade)
+		}
+	}
+
+	if math.IsInf(max, -1) {
+		return (s.Elevation.Data[end] - s.Elevation.Data[start]) / (s.Distance.Data[end] - s.Distance.Data[start]) * 100
+	}
+
+	return max
+}
+
+// ClimbCategoryFor classifies a climb using the product of its
+// length in meters and average grade in percent.
+func ClimbCategoryFor(distance, averageGrade float64) ClimbCategory {
+	score := distance * averageGrade
+
+	switch {
+	case score >= 80000:
+		return ClimbCategories.HorsCategorie
+	case score >= 64000:
+		return ClimbCategories.Category1
+	case score >= 32000:
+		return ClimbCategories.Category2
+	case score >= 16000:
+		return ClimbCategories.Category3
+	case score >= 8000:
+		return ClimbCategories.Category4
+	}
+
+	return ClimbCategories.NotCategorized
+}
@@ -0,0 +1,163 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStreamSetClimbs(t *testing.T) {
+	set := &StreamSet{
+		Time:      &IntegerStream{Data: make([]int, 0)},
+		Distance:  &DecimalStream{Data: make([]float64, 0)},
+		Elevation: &DecimalStream{Data: make([]float64, 0)},
+	}
+
+	// 1km flat, 2km at 5% with a 5 meter dip and 5 meter ramp, 1km flat, 1km down, 200 meters at 5%
+	elevation := 100.0
+	for i := 0; i <= 520; i++ {
+		d := float64(i * 10)
+		switch {
+		case d > 1000 && d <= 3000:
+			elevation += 0.5
+			if d > 2000 && d <= 2050 {
+				elevation -= 1.5
+			} else if d > 2050 && d <= 2100 {
+				elevation += 0.5
+			}
+		case d > 4000 && d <= 5000:
+			elevation -= 1
+		case d > 5000:
+			elevation += 0.5
+		}
+
+		set.Time.Data = append(set.Time.Data, 2*i)
+		set.Distance.Data = append(set.Distance.Data, d)
+		set.Elevation.Data = append(set.Elevation.Data, elevation)
+	}
+
+	climbs, err := set.Climbs()
+	if err != nil {
+		t.Fatalf("climbs error: %v", err)
+	}
+
+	if len(climbs) != 1 {
+		t.Fatalf("incorrect number of climbs, got %d", len(climbs))
+	}
+
+	c := climbs[0]
+	if c.StartIndex != 100 || c.EndIndex != 300 {
+		t.Errorf("climb indexes incorrect, got %d %d", c.StartIndex, c.EndIndex)
+	}
+
+	if c.Distance != 2000 || c.ElevationGain != 95 || c.AverageGrade != 4.75 {
+		t.Errorf("climb incorrect, got %v", c)
+	}
+
+	// the ramp and following 50 meters
+	if math.Abs(c.MaximumGrade-7.5) > 1e-9 {
+		t.Errorf("maximum grade incorrect, got %v", c.MaximumGrade)
+	}
+
+	if c.VAM != 855 {
+		t.Errorf("vam incorrect, got %v", c.VAM)
+	}
+
+	if c.ClimbCategory != ClimbCategories.Category4 {
+		t.Errorf("climb category incorrect, got %v", c.ClimbCategory)
+	}
+
+	// a dip larger than allowed splits the climb, and the halves are too small
+	climbs, _ = set.Climbs(ClimbDetectionOptions{MinimumGain: 60, MinimumGrade: 3, MaximumDip: 2, GradeDistance: 100})
+	if len(climbs) != 0 {
+		t.Errorf("incorrect number of climbs, got %d", len(climbs))
+	}
+
+	if _, err := (&StreamSet{}).Climbs(); err == nil {
+		t.Error("should return error if streams missing")
+	}
+}
+
+func TestClimbCategoryFor(t *testing.T) {
+	// Hawk Hill, see segment_get cassette
+	if c := ClimbCategoryFor(2684.82, 5.7); c != ClimbCategories.Category4 {
+		t.Errorf("climb category incorrect, got %v", c)
+	}
+
+	if c := ClimbCategoryFor(1000, 2); c != ClimbCategories.NotCategorized {
+		t.Errorf("climb category incorrect, got %v", c)
+	}
+
+	if c := ClimbCategoryFor(10000, 7); c != ClimbCategories.Category1 {
+		t.Errorf("climb category incorrect, got %v", c)
+	}
+
+	if c := ClimbCategoryFor(20000, 7); c != ClimbCategories.HorsCategorie {
+		t.Errorf("climb category incorrect, got %v", c)
+	}
+}
+
+func TestStreamSetClimbsLargeDip(t *testing.T) {
+	// 500 meters at 5% then straight down past the start
+	set := &StreamSet{
+		Time:      &IntegerStream{Data: make([]int, 0)},
+		Distance:  &DecimalStream{Data: make([]float64, 0)},
+		Elevation: &DecimalStream{Data: make([]float64, 0)},
+	}
+
+	for i := 0; i <= 100; i++ {
+		elevation := float64(i) / 2
+		if i > 50 {
+			elevation = 25 - float64(i-50)
+		}
+
+		set.Time.Data = append(set.Time.Data, i)
+		set.Distance.Data = append(set.Distance.Data, float64(10*i))
+		set.Elevation.Data = append(set.Elevation.Data, elevation)
+	}
+
+	climbs, err := set.Climbs(ClimbDetectionOptions{MinimumGain: 20, MinimumGrade: 3, MaximumDip: 30, GradeDistance: 100})
+	if err != nil {
+		t.Fatalf("climbs error: %v", err)
+	}
+
+	if len(climbs) != 1 {
+		t.Fatalf("climb larger than the dip should be kept, got %d climbs", len(climbs))
+	}
+
+	if c := climbs[0]; c.StartIndex != 0 || c.EndIndex != 50 || c.ElevationGain != 25 {
+		t.Errorf("climb incorrect, got %v", c)
+	}
+}
+
+func TestStreamSetClimbsFalseFlat(t *testing.T) {
+	set := &StreamSet{
+		Distance:  &DecimalStream{Data: make([]float64, 0)},
+		Elevation: &DecimalStream{Data: make([]float64, 0)},
+	}
+
+	// 4km at 1% then 1km at 8%
+	elevation := 0.0
+	for i := 0; i <= 500; i++ {
+		set.Distance.Data = append(set.Distance.Data, float64(10*i))
+		set.Elevation.Data = append(set.Elevation.Data, elevation)
+
+		if i < 400 {
+			elevation += 0.1
+		} else {
+			elevation += 0.8
+		}
+	}
+
+	climbs, err := set.Climbs()
+	if err != nil {
+		t.Fatalf("climbs error: %v", err)
+	}
+
+	if len(climbs) != 1 {
+		t.Fatalf("climb after a false flat should be found, got %d climbs", len(climbs))
+	}
+
+	if c := climbs[0]; c.StartIndex <= 0 || c.StartIndex >= 400 || c.EndIndex != 500 || c.AverageGrade < 3 {
+		t.Errorf("climb should start on the false flat, got %v", c)
+	}
+}
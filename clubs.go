@@ -3,6 +3,7 @@ package strava
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 type ClubDetailed struct {
@@ -24,6 +25,57 @@ type ClubSummary struct {
 	Profile       string `json:"profile"`        // URL to a 124x124 pixel profile picture
 }
 
+// ClubMembership is the result of joining or leaving a club.
+// Membership is "member" or "pending" if the club is private.
+type ClubMembership struct {
+	Success    bool   `json:"success"`
+	Active     bool   `json:"active"`
+	Membership string `json:"membership"`
+}
+
+type ClubAnnouncement struct {
+	Id        int64          `json:"id"`
+	ClubId    int64          `json:"club_id"`
+	Athlete   AthleteSummary `json:"athlete"`
+	CreatedAt time.Time      `json:"created_at"`
+	Message   string         `json:"message"`
+}
+
+type GroupEvent struct {
+	Id                  int64          `json:"id"`
+	Title               string         `json:"title"`
+	Description         string         `json:"description"`
+	ClubId              int64          `json:"club_id"`
+	OrganizingAthlete   AthleteSummary `json:"organizing_athlete"`
+	ActivityType        ActivityType   `json:"activity_type"`
+	CreatedAt           time.Time      `json:"created_at"`
+	RouteId             int64          `json:"route_id"`
+	WomenOnly           bool           `json:"woman_only"`
+	Private             bool           `json:"private"`
+	SkillLevel          SkillLevel     `json:"skill_levels"`
+	Terrain             Terrain        `json:"terrain"`
+	UpcomingOccurrences []time.Time    `json:"upcoming_occurrences"`
+	Address             string         `json:"address"`
+	StartLocation       Location       `json:"start_latlng"`
+	Joined              bool           `json:"joined"`
+}
+
+type SkillLevel int
+
+var SkillLevels = struct {
+	Casual     SkillLevel
+	Tempo      SkillLevel
+	Hammerfest SkillLevel
+}{1, 2, 4}
+
+type Terrain int
+
+var Terrains = struct {
+	MostlyFlat   Terrain
+	RollingHills Terrain
+	KillerClimbs Terrain
+}{0, 1, 2}
+
 type ClubType string
 
 var ClubTypes = struct {
@@ -161,3 +213,264 @@ func (c *ClubListActivitiesCall) Do() ([]*ActivitySummary, error) {
 
 	return activities, nil
 }
+
+/*********************************************************/
+
+type ClubJoinCall struct {
+	service *ClubsService
+	id      int64
+	action  string
+}
+
+// Join makes the current athlete a member of the club, or requests membership if the club is private.
+func (s *ClubsService) Join(clubId int64) *ClubJoinCall {
+	return &ClubJoinCall{
+		service: s,
+		id:      clubId,
+		action:  "join",
+	}
+}
+
+func (s *ClubsService) Leave(clubId int64) *ClubJoinCall {
+	return &ClubJoinCall{
+		service: s,
+		id:      clubId,
+		action:  "leave",
+	}
+}
+
+func (c *ClubJoinCall) Do() (*ClubMembership, error) {
+	data, err := c.service.client.run("POST", fmt.Sprintf("/clubs/%d/%s", c.id, c.action), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var membership ClubMembership
+	err = json.Unmarshal(data, &membership)
+	if err != nil {
+		return nil, err
+	}
+
+	return &membership, nil
+}
+
+/*********************************************************/
+
+type ClubListAdminsCall struct {
+	service *ClubsService
+	id      int64
+	ops     map[string]interface{}
+}
+
+func (s *ClubsService) ListAdmins(clubId int64) *ClubListAdminsCall {
+	return &ClubListAdminsCall{
+		service: s,
+		id:      clubId,
+		ops:     make(map[string]interface{}),
+	}
+}
+
+func (c *ClubListAdminsCall) Page(page int) *ClubListAdminsCall {
+	c.ops["page"] = page
+	return c
+}
+
+func (c *ClubListAdminsCall) PerPage(perPage int) *ClubListAdminsCall {
+	c.ops["per_page"] = perPage
+	return c
+}
+
+func (c *ClubListAdminsCall) Do() ([]*AthleteSummary, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/clubs/%d/admins", c.id), c.ops)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := make([]*AthleteSummary, 0)
+	err = json.Unmarshal(data, &admins)
+	if err != nil {
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+/*********************************************************/
+
+type ClubListAnnouncementsCall struct {
+	service *ClubsService
+	id      int64
+}
+
+func (s *ClubsService) ListAnnouncements(clubId int64) *ClubListAnnouncementsCall {
+	return &ClubListAnnouncementsCall{
+		service: s,
+		id:      clubId,
+	}
+}
+
+func (c *ClubListAnnouncementsCall) Do() ([]*ClubAnnouncement, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/clubs/%d/announcements", c.id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	announcements := make([]*ClubAnnouncement, 0)
+	err = json.Unmarshal(data, &announcements)
+	if err != nil {
+		return nil, err
+	}
+
+	return announcements, nil
+}
+
+/*********************************************************/
+
+type ClubListGroupEventsCall struct {
+	service *ClubsService
+	id      int64
+	ops     map[string]interface{}
+}
+
+func (s *ClubsService) ListGroupEvents(clubId int64) *ClubListGroupEventsCall {
+	return &ClubListGroupEventsCall{
+		service: s,
+		id:      clubId,
+		ops:     make(map[string]interface{}),
+	}
+}
+
+// Upcoming limits the list to events that have not happened yet.
+func (c *ClubListGroupEventsCall) Upcoming(upcoming bool) *ClubListGroupEventsCall {
+	c.ops["upcoming"] = upcoming
+	return c
+}
+
+func (c *ClubListGroupEventsCall) Do() ([]*GroupEvent, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/clubs/%d/group_events", c.id), c.ops)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*GroupEvent, 0)
+	err = json.Unmarshal(data, &events)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+/*********************************************************/
+
+type ClubGetGroupEventCall struct {
+	service *ClubsService
+	id      int64
+}
+
+func (s *ClubsService) GetGroupEvent(groupEventId int64) *ClubGetGroupEventCall {
+	return &ClubGetGroupEventCall{
+		service: s,
+		id:      groupEventId,
+	}
+}
+
+func (c *ClubGetGroupEventCall) Do() (*GroupEvent, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/group_events/%d", c.id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var event GroupEvent
+	err = json.Unmarshal(data, &event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &event, nil
+}
+
+/*********************************************************/
+
+type ClubJoinGroupEventCall struct {
+	service *ClubsService
+	id      int64
+	method  string
+}
+
+// JoinGroupEvent RSVPs the current athlete to the event.
+func (s *ClubsService) JoinGroupEvent(groupEventId int64) *ClubJoinGroupEventCall {
+	return &ClubJoinGroupEventCall{
+		service: s,
+		id:      groupEventId,
+		method:  "POST",
+	}
+}
+
+func (s *ClubsService) LeaveGroupEvent(groupEventId int64) *ClubJoinGroupEventCall {
+	return &ClubJoinGroupEventCall{
+		service: s,
+		id:      groupEventId,
+		method:  "DELETE",
+	}
+}
+
+// Do returns whether the current athlete is now attending the event.
+func (c *ClubJoinGroupEventCall) Do() (bool, error) {
+	data, err := c.service.client.run(c.method, fmt.Sprintf("/group_events/%d/rsvps", c.id), nil)
+	if err != nil {
+		return false, err
+	}
+
+	var rsvp struct {
+		Joined bool `json:"joined"`
+	}
+
+	err = json.Unmarshal(data, &rsvp)
+	if err != nil {
+		return false, err
+	}
+
+	return rsvp.Joined, nil
+}
+
+/*********************************************************/
+
+type ClubListGroupEventAthletesCall struct {
+	service *ClubsService
+	id      int64
+	ops     map[string]interface{}
+}
+
+func (s *ClubsService) ListGroupEventAthletes(groupEventId int64) *ClubListGroupEventAthletesCall {
+	return &ClubListGroupEventAthletesCall{
+		service: s,
+		id:      groupEventId,
+		ops:     make(map[string]interface{}),
+	}
+}
+
+func (c *ClubListGroupEventAthletesCall) Page(page int) *ClubListGroupEventAthletesCall {
+	c.ops["page"] = page
+	return c
+}
+
+func (c *ClubListGroupEventAthletesCall) PerPage(perPage int) *ClubListGroupEventAthletesCall {
+	c.ops["per_page"] = perPage
+	return c
+}
+
+func (c *ClubListGroupEventAthletesCall) Do() ([]*AthleteSummary, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/group_events/%d/athletes", c.id), c.ops)
+	if err != nil {
+		return nil, err
+	}
+
+	athletes := make([]*AthleteSummary, 0)
+	err = json.Unmarshal(data, &athletes)
+	if err != nil {
+		return nil, err
+	}
+
+	return athletes, nil
+}
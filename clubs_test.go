@@ -118,6 +118,190 @@ func TestClubsListActivities(t *testing.T) {
 	}
 }
 
+func TestClubsJoinLeave(t *testing.T) {
+	client := NewStubResponseClient(`{"success":true,"active":false,"membership":"pending"}`)
+	membership, err := NewClubsService(client).Join(45255).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	expected := &ClubMembership{Success: true, Active: false, Membership: "pending"}
+	if !reflect.DeepEqual(membership, expected) {
+		t.Errorf("should match\n%v\n%v", membership, expected)
+	}
+
+	// from here on out just check the request parameters
+	s := NewClubsService(newStoreRequestClient())
+
+	s.Join(321).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/clubs/321/join" || transport.request.Method != "POST" {
+		t.Errorf("request incorrect, got %v %v", transport.request.Method, transport.request.URL.Path)
+	}
+
+	s.Leave(321).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/clubs/321/leave" || transport.request.Method != "POST" {
+		t.Errorf("request incorrect, got %v %v", transport.request.Method, transport.request.URL.Path)
+	}
+}
+
+func TestClubsListAdmins(t *testing.T) {
+	client := NewStubResponseClient(`[{"id":227615,"firstname":"John","lastname":"Applestrava"}]`)
+	admins, err := NewClubsService(client).ListAdmins(45255).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(admins) != 1 || admins[0].Id != 227615 || admins[0].FirstName != "John" {
+		t.Errorf("admins not parsed, got %v", admins)
+	}
+
+	// from here on out just check the request parameters
+	s := NewClubsService(newStoreRequestClient())
+
+	// path
+	s.ListAdmins(321).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/clubs/321/admins" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	// parameters
+	s.ListAdmins(321).PerPage(9).Page(2).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.RawQuery != "page=2&per_page=9" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+}
+
+func TestClubsListAnnouncements(t *testing.T) {
+	client := NewStubResponseClient(`[{"id":118,"resource_state":2,"club_id":45255,"athlete":{"id":227615,"firstname":"John"},"created_at":"2014-01-02T18:18:18Z","message":"Ride on Saturday"}]`)
+	announcements, err := NewClubsService(client).ListAnnouncements(45255).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(announcements) != 1 {
+		t.Fatalf("announcements not parsed, got %v", announcements)
+	}
+
+	a := announcements[0]
+	if a.Id != 118 || a.ClubId != 45255 || a.Athlete.Id != 227615 || a.Message != "Ride on Saturday" {
+		t.Errorf("announcement incorrect, got %v", a)
+	}
+
+	if a.CreatedAt.IsZero() {
+		t.Error("announcement created at not parsed")
+	}
+
+	// path
+	s := NewClubsService(newStoreRequestClient())
+	s.ListAnnouncements(321).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/clubs/321/announcements" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+}
+
+const testGroupEventJSON = `{"id":2541,"resource_state":3,"title":"Saturday Hawk Hill","description":"Meet at the bridge","club_id":45255,"organizing_athlete":{"id":227615,"firstname":"John"},"activity_type":"Ride","created_at":"2014-01-02T18:18:18Z","route_id":1234,"woman_only":false,"private":false,"skill_levels":2,"terrain":1,"upcoming_occurrences":["2014-01-04T16:00:00Z"],"address":"Golden Gate Bridge","start_latlng":[37.8,-122.47],"joined":true}`
+
+func TestClubsGroupEvents(t *testing.T) {
+	client := NewStubResponseClient(testGroupEventJSON)
+	event, err := NewClubsService(client).GetGroupEvent(2541).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if event.Id != 2541 || event.ClubId != 45255 || event.OrganizingAthlete.Id != 227615 || event.RouteId != 1234 {
+		t.Errorf("event incorrect, got %v", event)
+	}
+
+	if event.ActivityType != ActivityTypes.Ride || event.SkillLevel != SkillLevels.Tempo || event.Terrain != Terrains.RollingHills {
+		t.Errorf("event types incorrect, got %v %v %v", event.ActivityType, event.SkillLevel, event.Terrain)
+	}
+
+	if len(event.UpcomingOccurrences) != 1 || event.StartLocation != (Location{37.8, -122.47}) || !event.Joined {
+		t.Errorf("event incorrect, got %v", event)
+	}
+
+	client = NewStubResponseClient("[" + testGroupEventJSON + "]")
+	events, err := NewClubsService(client).ListGroupEvents(45255).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Id != 2541 {
+		t.Errorf("events not parsed, got %v", events)
+	}
+
+	joined, err := NewClubsService(NewStubResponseClient(`{"joined":true}`)).JoinGroupEvent(2541).Do()
+	if err != nil || !joined {
+		t.Errorf("should have joined, got %v %v", joined, err)
+	}
+
+	joined, err = NewClubsService(NewStubResponseClient(`{"joined":false}`)).LeaveGroupEvent(2541).Do()
+	if err != nil || joined {
+		t.Errorf("should have left, got %v %v", joined, err)
+	}
+
+	// from here on out just check the request parameters
+	s := NewClubsService(newStoreRequestClient())
+
+	s.GetGroupEvent(321).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/group_events/321" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	s.ListGroupEvents(321).Upcoming(true).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/clubs/321/group_events" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	if transport.request.URL.RawQuery != "upcoming=true" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+
+	s.JoinGroupEvent(321).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/group_events/321/rsvps" || transport.request.Method != "POST" {
+		t.Errorf("request incorrect, got %v %v", transport.request.Method, transport.request.URL.Path)
+	}
+
+	s.LeaveGroupEvent(321).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/group_events/321/rsvps" || transport.request.Method != "DELETE" {
+		t.Errorf("request incorrect, got %v %v", transport.request.Method, transport.request.URL.Path)
+	}
+
+	s.ListGroupEventAthletes(321).Page(3).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/group_events/321/athletes" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	if transport.request.URL.RawQuery != "page=3" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+}
+
 func TestClubsBadJSON(t *testing.T) {
 	var err error
 	s := NewClubsService(NewStubResponseClient("bad json"))
@@ -136,4 +320,39 @@ func TestClubsBadJSON(t *testing.T) {
 	if err == nil {
 		t.Error("should return a bad json error")
 	}
+
+	_, err = s.Join(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.ListAdmins(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.ListAnnouncements(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.ListGroupEvents(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.GetGroupEvent(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.JoinGroupEvent(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.ListGroupEventAthletes(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
 }
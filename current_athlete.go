@@ -95,6 +95,34 @@ func (c *CurrentAthletePutCall) Do() (*AthleteDetailed, error) {
 
 /*********************************************************/
 
+type CurrentAthleteZonesCall struct {
+	service *CurrentAthleteService
+}
+
+// Zones returns the athlete's configured heart rate and power zones.
+func (s *CurrentAthleteService) Zones() *CurrentAthleteZonesCall {
+	return &CurrentAthleteZonesCall{
+		service: s,
+	}
+}
+
+func (c *CurrentAthleteZonesCall) Do() (*AthleteZones, error) {
+	data, err := c.service.client.run("GET", "/athlete/zones", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var zones AthleteZones
+	err = json.Unmarshal(data, &zones)
+	if err != nil {
+		return nil, err
+	}
+
+	return &zones, nil
+}
+
+/*********************************************************/
+
 type CurrentAthleteListActivitiesCall struct {
 	service *CurrentAthleteService
 	ops     map[string]interface{}
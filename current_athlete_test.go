@@ -126,6 +126,38 @@ func TestCurrentAthleteUpdate(t *testing.T) {
 	}
 }
 
+func TestCurrentAthleteZones(t *testing.T) {
+	client := NewStubResponseClient(`{"heart_rate":{"custom_zones":true,"zones":[{"min":0,"max":115},{"min":115,"max":152},{"min":152,"max":171},{"min":171,"max":190},{"min":190,"max":-1}]},"power":{"zones":[{"min":0,"max":137},{"min":137,"max":186},{"min":186,"max":-1}]}}`)
+	zones, err := NewCurrentAthleteService(client).Zones().Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !zones.HeartRate.CustomZones || len(zones.HeartRate.Zones) != 5 {
+		t.Errorf("heart rate zones incorrect, got %v", zones.HeartRate)
+	}
+
+	if z := zones.HeartRate.Zones[4]; z.Min != 190 || z.Max != -1 {
+		t.Errorf("heart rate zone incorrect, got %v", z)
+	}
+
+	if len(zones.Power.Zones) != 3 || zones.Power.Zones.Zone(150) != 1 {
+		t.Errorf("power zones incorrect, got %v", zones.Power)
+	}
+
+	// from here on out just check the request parameters
+	s := NewCurrentAthleteService(newStoreRequestClient())
+
+	// path
+	s.Zones().Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/athlete/zones" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+}
+
 func TestCurrentAthleteListActivities(t *testing.T) {
 	client := newCassetteClient(testToken, "current_athlete_list_activities")
 	activities, err := NewCurrentAthleteService(client).ListActivities().Do()
@@ -348,6 +380,11 @@ func TestCurrentAthleteBadJSON(t *testing.T) {
 		t.Error("should return a bad json error")
 	}
 
+	_, err = s.Zones().Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
 	_, err = s.ListActivities().Do()
 	if err == nil {
 		t.Error("should return a bad json error")
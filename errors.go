@@ -2,6 +2,7 @@ package strava
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 type Error struct {
@@ -36,3 +37,22 @@ var (
 	OAuthInvalidCodeErr         = &OAuthError{"unrecognized code"}
 	OAuthServerErr              = &OAuthError{"server error"}
 )
+
+// returned when decoding a malformed polyline
+type PolylineError struct {
+	Offset  int // position in the polyline of the problem
+	message string
+}
+
+func (e *PolylineError) Error() string {
+	return fmt.Sprintf("polyline %s at offset %d", e.message, e.Offset)
+}
+
+// returned when unmarshaling an object stored by a newer version of this package
+type SchemaVersionError struct {
+	Version int // version of the stored object
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("schema version %d is newer than %d", e.Version, SchemaVersion)
+}
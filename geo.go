@@ -0,0 +1,420 @@
+package strava
+
+import (
+	"container/heap"
+	"math"
+)
+
+const earthRadius = 6371000.0 // meters, mean radius
+
+// WGS-84 ellipsoid, used by Vincenty's formulae
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+)
+
+// Bounds is a latitude/longitude bounding box, as used by SegmentsService.Explore.
+type Bounds struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// IsNullIsland returns true for [0, 0], which Strava uses for unavailable locations.
+func (l Location) IsNullIsland() bool {
+	return l[0] == 0 && l[1] == 0
+}
+
+// DistanceTo returns the great circle distance in meters to the other location
+// using the haversine formula on a spherical earth.
+func (l Location) DistanceTo(other Location) float64 {
+	lat1 := toRadians(l[0])
+	lat2 := toRadians(other[0])
+	dLat := lat2 - lat1
+	dLng := toRadians(other[1] - l[1])
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// VincentyDistanceTo returns the distance in meters to the other location on the
+// WGS-84 ellipsoid using Vincenty's inverse formula. It is accurate to within millimeters
+// but slower than DistanceTo. For nearly antipodal points, where the formula does not
+// converge, the haversine distance is returned.
+func (l Location) VincentyDistanceTo(other Location) float64 {
+	L := toRadians(other[1] - l[1])
+	U1 := math.Atan((1 - wgs84F) * math.Tan(toRadians(l[0])))
+	U2 := math.Atan((1 - wgs84F) * math.Tan(toRadians(other[0])))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	var sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+
+	converged := false
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma = math.Sqrt((cosU2*sinLambda)*(cosU2*sinLambda) +
+			(cosU1*sinU2-sinU1*cosU2*cosLambda)*(cosU1*sinU2-sinU1*cosU2*cosLambda))
+
+		if sinSigma == 0 {
+			return 0 // same point
+		}
+
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+
+		cos2SigmaM = 0 // equatorial line
+		if cosSqAlpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+
+		C := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = L + (1-C)*wgs84F*sinAlpha*
+			(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+
+		if math.Abs(lambda-prev) < 1e-12 {
+			converged = true
+			break
+		}
+	}
+
+	if !converged {
+		return l.DistanceTo(other)
+	}
+
+	uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	return wgs84B * A * (sigma - deltaSigma)
+}
+
+// BearingTo returns the initial bearing in degrees, 0-360 clockwise from north,
+// of the great circle path to the other location.
+func (l Location) BearingTo(other Location) float64 {
+	lat1 := toRadians(l[0])
+	lat2 := toRadians(other[0])
+	dLng := toRadians(other[1] - l[1])
+
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+
+	return math.Mod(toDegrees(math.Atan2(y, x))+360, 360)
+}
+
+// DistanceToLine returns the shortest distance in meters from the location
+// to the line through the given points, eg. a decoded Polyline.
+func (l Location) DistanceToLine(points [][2]float64) float64 {
+	d, _, _ := l.nearestOnLine(points)
+	return d
+}
+
+// nearestOnLine returns the distance to the line, the index of the closest line segment
+// and the fraction along that segment of the closest point.
+func (l Location) nearestOnLine(points [][2]float64) (float64, int, float64) {
+	if len(points) == 0 {
+		return math.Inf(1), -1, 0
+	}
+
+	if len(points) == 1 {
+		return l.DistanceTo(points[0]), 0, 0
+	}
+
+	// project into meters around the location, accurate enough over short distances
+	proj := newLocalProjection(l[0])
+	px, py := 0.0, 0.0
+
+	best, bestIndex, bestFraction := math.Inf(1), -1, 0.0
+	for i := 1; i < len(points); i++ {
+		ax, ay := proj.project(l, points[i-1])
+		bx, by := proj.project(l, points[i])
+
+		t := segmentFraction(px, py, ax, ay, bx, by)
+		x, y := ax+t*(bx-ax), ay+t*(by-ay)
+
+		if d := math.Hypot(px-x, py-y); d < best {
+			best, bestIndex, bestFraction = d, i-1, t
+		}
+	}
+
+	return best, bestIndex, bestFraction
+}
+
+// NewBounds returns the bounding box of the points, eg. a decoded Polyline.
+// Null Island points are ignored.
+func NewBounds(points [][2]float64) Bounds {
+	b := Bounds{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+
+	for _, p := range points {
+		if Location(p).IsNullIsland() {
+			continue
+		}
+
+		b.South = math.Min(b.South, p[0])
+		b.West = math.Min(b.West, p[1])
+		b.North = math.Max(b.North, p[0])
+		b.East = math.Max(b.East, p[1])
+	}
+
+	if math.IsInf(b.South, 1) {
+		return Bounds{}
+	}
+
+	return b
+}
+
+// Contains returns true if the location is within the bounds.
+func (b Bounds) Contains(l Location) bool {
+	return l[0] >= b.South && l[0] <= b.North && l[1] >= b.West && l[1] <= b.East
+}
+
+// Center returns the middle of the bounds.
+func (b Bounds) Center() Location {
+	return Location{(b.South + b.North) / 2, (b.West + b.East) / 2}
+}
+
+// Centroid returns the center of mass of the line through the points, each line segment
+// weighted by its length. Null Island points are ignored.
+func Centroid(points [][2]float64) Location {
+	var lat, lng, total float64
+	var last Location
+
+	count := 0
+	for _, p := range points {
+		l := Location(p)
+		if l.IsNullIsland() {
+			continue
+		}
+
+		if count > 0 {
+			d := last.DistanceTo(l)
+			lat += d * (last[0] + l[0]) / 2
+			lng += d * (last[1] + l[1]) / 2
+			total += d
+		}
+
+		last = l
+		count++
+	}
+
+	if total > 0 {
+		return Location{lat / total, lng / total}
+	}
+
+	// all the same point, or none at all
+	return last
+}
+
+// SimplifyDouglasPeucker reduces the number of points in the line using the
+// Ramer-Douglas-Peucker algorithm. Points within tolerance meters of the
+// simplified line are removed. The first and last points are always kept.
+func SimplifyDouglasPeucker(points [][2]float64, tolerance float64) [][2]float64 {
+	if len(points) < 3 {
+		return copyPoints(points)
+	}
+
+	proj := newLocalProjection(NewBounds(points).Center()[0])
+	origin := Location(points[0])
+
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i], ys[i] = proj.project(origin, p)
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		first, last := r[0], r[1]
+		index, max := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			t := segmentFraction(xs[i], ys[i], xs[first], ys[first], xs[last], ys[last])
+			x, y := xs[first]+t*(xs[last]-xs[first]), ys[first]+t*(ys[last]-ys[first])
+
+			if d := math.Hypot(xs[i]-x, ys[i]-y); d > max {
+				index, max = i, d
+			}
+		}
+
+		if index >= 0 {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+
+	result := make([][2]float64, 0)
+	for i, p := range points {
+		if keep[i] {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+// SimplifyVisvalingam reduces the number of points in the line using the
+// Visvalingam-Whyatt algorithm. Points are removed, smallest first, while the triangle
+// they form with their neighbors is less than minimumArea square meters.
+// The first and last points are always kept.
+func SimplifyVisvalingam(points [][2]float64, minimumArea float64) [][2]float64 {
+	if len(points) < 3 {
+		return copyPoints(points)
+	}
+
+	proj := newLocalProjection(NewBounds(points).Center()[0])
+	origin := Location(points[0])
+
+	vertices := make([]*visvalingamVertex, len(points))
+	for i, p := range points {
+		v := &visvalingamVertex{index: i, prev: i - 1, next: i + 1}
+		v.x, v.y = proj.project(origin, p)
+		vertices[i] = v
+	}
+
+	area := func(v *visvalingamVertex) float64 {
+		a, b := vertices[v.prev], vertices[v.next]
+		return math.Abs((a.x-v.x)*(b.y-v.y)-(b.x-v.x)*(a.y-v.y)) / 2
+	}
+
+	h := make(visvalingamHeap, 0, len(points)-2)
+	for _, v := range vertices[1 : len(points)-1] {
+		v.area = area(v)
+		v.heapIndex = len(h)
+		h = append(h, v)
+	}
+	heap.Init(&h)
+
+	removed := make([]bool, len(points))
+	for h.Len() > 0 {
+		v := heap.Pop(&h).(*visvalingamVertex)
+		if v.area >= minimumArea {
+			break
+		}
+		removed[v.index] = true
+
+		prev, next := vertices[v.prev], vertices[v.next]
+		prev.next = next.index
+		next.prev = prev.index
+
+		// neighbors can't be removed before the point just removed
+		for _, n := range []*visvalingamVertex{prev, next} {
+			if n.heapIndex >= 0 && n.index != 0 && n.index != len(points)-1 {
+				n.area = math.Max(area(n), v.area)
+				heap.Fix(&h, n.heapIndex)
+			}
+		}
+	}
+
+	result := make([][2]float64, 0)
+	for i, p := range points {
+		if !removed[i] {
+			result = append(result, p)
+		}
+	}
+
+	return result
+}
+
+/*********************************************************/
+
+type visvalingamVertex struct {
+	index, prev, next int
+	x, y, area        float64
+	heapIndex         int
+}
+
+type visvalingamHeap []*visvalingamVertex
+
+func (h visvalingamHeap) Len() int           { return len(h) }
+func (h visvalingamHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+
+func (h visvalingamHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *visvalingamHeap) Push(x interface{}) {
+	v := x.(*visvalingamVertex)
+	v.heapIndex = len(*h)
+	*h = append(*h, v)
+}
+
+func (h *visvalingamHeap) Pop() interface{} {
+	old := *h
+	v := old[len(old)-1]
+	v.heapIndex = -1
+	*h = old[:len(old)-1]
+	return v
+}
+
+// mercator returns the web mercator position of the [lat, lng] point, with x and y between 0 and 1
+// starting from the top left. Latitudes are clamped to the mercator limit.
+func mercator(point [2]float64) (float64, float64) {
+	lat := math.Max(-85.05112878, math.Min(85.05112878, point[0]))
+	sinLat := math.Sin(toRadians(lat))
+
+	x := (point[1] + 180) / 360
+	y := 0.5 - math.Log((1+sinLat)/(1-sinLat))/(4*math.Pi)
+	return x, y
+}
+
+// localProjection is an equirectangular projection into meters,
+// accurate enough for the distances covered by an activity.
+type localProjection struct {
+	cosLat float64
+}
+
+func newLocalProjection(latitude float64) localProjection {
+	return localProjection{math.Cos(toRadians(latitude))}
+}
+
+// project returns the x, y position of the point in meters relative to the origin.
+func (p localProjection) project(origin Location, point [2]float64) (float64, float64) {
+	x := toRadians(point[1]-origin[1]) * p.cosLat * earthRadius
+	y := toRadians(point[0]-origin[0]) * earthRadius
+	return x, y
+}
+
+// segmentFraction returns how far along the segment a to b, between 0 and 1,
+// the closest point to p is.
+func segmentFraction(px, py, ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSq := dx*dx + dy*dy
+	if lengthSq == 0 {
+		return 0
+	}
+
+	t := ((px-ax)*dx + (py-ay)*dy) / lengthSq
+	return math.Max(0, math.Min(1, t))
+}
+
+func copyPoints(points [][2]float64) [][2]float64 {
+	result := make([][2]float64, len(points))
+	copy(result, points)
+	return result
+}
+
+func toRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func toDegrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
@@ -0,0 +1,330 @@
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// A GeoJSONFeature is a LineString GeoJSON Feature, see http://geojson.org.
+// Properties are the json fields of the exported object, without the geometry.
+// Geometry is nil, null in json, if there are fewer than two points, eg. for manual activities.
+// Likewise KML placemarks have no LineString, and WKT is "LINESTRING EMPTY".
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   *GeoJSONGeometry       `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// GeoJSONGeometry coordinates are in [lng, lat] order as required by the spec,
+// the opposite of Location.
+type GeoJSONGeometry struct {
+	Type        string       `json:"type"`
+	Coordinates [][2]float64 `json:"coordinates"`
+}
+
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*GeoJSONFeature `json:"features"`
+}
+
+/*********************************************************/
+
+// GeoJSON exports the activity's route, the detailed Map.Polyline if available
+// otherwise the Map.SummaryPolyline.
+func (a *ActivitySummary) GeoJSON() *GeoJSONFeature {
+	return newGeoJSONFeature(a.line(), a, "map")
+}
+
+// KML exports the activity's route as a KML document.
+func (a *ActivitySummary) KML() string {
+	return kmlDocument(kmlPlacemark(a.Name, a.line()))
+}
+
+// WKT exports the activity's route as a Well-known text LineString.
+func (a *ActivitySummary) WKT() string {
+	return wktLineString(a.line())
+}
+
+func (a *ActivitySummary) line() [][2]float64 {
+	if a.Map.Polyline != "" {
+		return a.Map.Polyline.Decode()
+	}
+
+	return a.Map.SummaryPolyline.Decode()
+}
+
+/*********************************************************/
+
+func (s *SegmentDetailed) GeoJSON() *GeoJSONFeature {
+	return newGeoJSONFeature(s.Map.Polyline.Decode(), s, "map")
+}
+
+func (s *SegmentDetailed) KML() string {
+	return kmlDocument(kmlPlacemark(s.Name, s.Map.Polyline.Decode()))
+}
+
+func (s *SegmentDetailed) WKT() string {
+	return wktLineString(s.Map.Polyline.Decode())
+}
+
+/*********************************************************/
+
+func (s *SegmentExplorerSegment) GeoJSON() *GeoJSONFeature {
+	return newGeoJSONFeature(s.Polyline.Decode(), s, "points")
+}
+
+func (s *SegmentExplorerSegment) KML() string {
+	return kmlDocument(kmlPlacemark(s.Name, s.Polyline.Decode()))
+}
+
+func (s *SegmentExplorerSegment) WKT() string {
+	return wktLineString(s.Polyline.Decode())
+}
+
+/*********************************************************/
+
+// GeoJSON exports the stream as a LineString, skipping Null Island points.
+func (s *LocationStream) GeoJSON() *GeoJSONFeature {
+	return newGeoJSONFeature(s.line(), s.Stream)
+}
+
+func (s *LocationStream) KML() string {
+	return kmlDocument(kmlPlacemark(string(s.Type), s.line()))
+}
+
+func (s *LocationStream) WKT() string {
+	return wktLineString(s.line())
+}
+
+func (s *LocationStream) line() [][2]float64 {
+	line := make([][2]float64, 0, len(s.Data))
+	for _, l := range s.Data {
+		if !Location(l).IsNullIsland() {
+			line = append(line, l)
+		}
+	}
+
+	return line
+}
+
+/*********************************************************/
+
+func NewGeoJSONFeatureCollection(features ...*GeoJSONFeature) *GeoJSONFeatureCollection {
+	collection := &GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]*GeoJSONFeature, 0, len(features)),
+	}
+
+	collection.Features = append(collection.Features, features...)
+	return collection
+}
+
+// ActivitiesGeoJSON exports a list of activities, eg. from ListActivities, as a FeatureCollection.
+func ActivitiesGeoJSON(activities []*ActivitySummary) *GeoJSONFeatureCollection {
+	collection := NewGeoJSONFeatureCollection()
+	for _, a := range activities {
+		collection.Features = append(collection.Features, a.GeoJSON())
+	}
+
+	return collection
+}
+
+func SegmentsGeoJSON(segments []*SegmentDetailed) *GeoJSONFeatureCollection {
+	collection := NewGeoJSONFeatureCollection()
+	for _, s := range segments {
+		collection.Features = append(collection.Features, s.GeoJSON())
+	}
+
+	return collection
+}
+
+// SegmentsKML exports a list of segments as a KML document with a placemark for each.
+func SegmentsKML(segments []*SegmentDetailed) string {
+	var placemarks bytes.Buffer
+	for _, s := range segments {
+		placemarks.WriteString(kmlPlacemark(s.Name, s.Map.Polyline.Decode()))
+	}
+
+	return kmlDocument(placemarks.String())
+}
+
+// SegmentExplorerGeoJSON exports the results of SegmentsService.Explore as a FeatureCollection.
+func SegmentExplorerGeoJSON(segments []*SegmentExplorerSegment) *GeoJSONFeatureCollection {
+	collection := NewGeoJSONFeatureCollection()
+	for _, s := range segments {
+		collection.Features = append(collection.Features, s.GeoJSON())
+	}
+
+	return collection
+}
+
+// ActivitiesKML exports a list of activities as a KML document with a placemark for each.
+func ActivitiesKML(activities []*ActivitySummary) string {
+	var placemarks bytes.Buffer
+	for _, a := range activities {
+		placemarks.WriteString(kmlPlacemark(a.Name, a.line()))
+	}
+
+	return kmlDocument(placemarks.String())
+}
+
+// SegmentExplorerKML exports the results of SegmentsService.Explore as a KML document.
+func SegmentExplorerKML(segments []*SegmentExplorerSegment) string {
+	var placemarks bytes.Buffer
+	for _, s := range segments {
+		placemarks.WriteString(kmlPlacemark(s.Name, s.Polyline.Decode()))
+	}
+
+	return kmlDocument(placemarks.String())
+}
+
+/*********************************************************/
+
+// GeoJSONEncoder writes a FeatureCollection one feature at a time,
+// so large archives don't need to be held in memory.
+// Close must be called to finish the collection.
+type GeoJSONEncoder struct {
+	writer io.Writer
+	count  int
+	closed bool
+}
+
+func NewGeoJSONEncoder(w io.Writer) *GeoJSONEncoder {
+	return &GeoJSONEncoder{writer: w}
+}
+
+// Encode writes the feature to the collection.
+func (e *GeoJSONEncoder) Encode(feature *GeoJSONFeature) error {
+	if e.closed {
+		return errors.New("geojson encoder closed")
+	}
+
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+
+	prefix := ","
+	if e.count == 0 {
+		prefix = `{"type":"FeatureCollection","features":[`
+	}
+
+	if _, err = io.WriteString(e.writer, prefix); err != nil {
+		return err
+	}
+
+	if _, err = e.writer.Write(data); err != nil {
+		return err
+	}
+
+	e.count++
+	return nil
+}
+
+// Close finishes the collection. It does not close the underlying writer.
+func (e *GeoJSONEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	if e.count == 0 {
+		_, err := io.WriteString(e.writer, `{"type":"FeatureCollection","features":[]}`)
+		return err
+	}
+
+	_, err := io.WriteString(e.writer, "]}")
+	return err
+}
+
+/*********************************************************/
+
+// newGeoJSONFeature uses the json encoding of object as the properties,
+// removing the given geometry fields.
+func newGeoJSONFeature(line [][2]float64, object interface{}, remove ...string) *GeoJSONFeature {
+	feature := &GeoJSONFeature{
+		Type:       "Feature",
+		Properties: make(map[string]interface{}),
+	}
+
+	// a LineString needs at least two positions
+	if len(line) >= 2 {
+		feature.Geometry = &GeoJSONGeometry{
+			Type:        "LineString",
+			Coordinates: make([][2]float64, len(line)),
+		}
+
+		for i, p := range line {
+			feature.Geometry.Coordinates[i] = [2]float64{p[1], p[0]}
+		}
+	}
+
+	data, _ := json.Marshal(object)
+	json.Unmarshal(data, &feature.Properties)
+
+	for _, r := range remove {
+		delete(feature.Properties, r)
+	}
+
+	return feature
+}
+
+func kmlDocument(placemarks string) string {
+	return xml.Header +
+		`<kml xmlns="http://www.opengis.net/kml/2.2"><Document>` +
+		placemarks +
+		`</Document></kml>`
+}
+
+func kmlPlacemark(name string, line [][2]float64) string {
+	var buf bytes.Buffer
+
+	buf.WriteString("<Placemark><name>")
+	xml.EscapeText(&buf, []byte(name))
+	buf.WriteString("</name>")
+
+	// a LineString needs at least two positions
+	if len(line) < 2 {
+		buf.WriteString("</Placemark>")
+		return buf.String()
+	}
+
+	buf.WriteString("<LineString><coordinates>")
+
+	for i, p := range line {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(formatCoordinate(p[1]) + "," + formatCoordinate(p[0]))
+	}
+
+	buf.WriteString("</coordinates></LineString></Placemark>")
+	return buf.String()
+}
+
+func wktLineString(line [][2]float64) string {
+	if len(line) < 2 {
+		return "LINESTRING EMPTY"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("LINESTRING (")
+	for i, p := range line {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		fmt.Fprintf(&buf, "%s %s", formatCoordinate(p[1]), formatCoordinate(p[0]))
+	}
+	buf.WriteString(")")
+
+	return buf.String()
+}
+
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
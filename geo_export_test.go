@@ -0,0 +1,193 @@
+package strava
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestActivityGeoJSON(t *testing.T) {
+	client := newCassetteClient(testToken, "activity_get")
+	activity, err := NewActivitiesService(client).Get(103221154).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	feature := activity.GeoJSON()
+	if feature.Type != "Feature" || feature.Geometry.Type != "LineString" {
+		t.Errorf("feature types incorrect, got %v %v", feature.Type, feature.Geometry.Type)
+	}
+
+	line := activity.Map.Polyline.Decode()
+	if len(feature.Geometry.Coordinates) != len(line) {
+		t.Fatalf("incorrect number of coordinates, got %d", len(feature.Geometry.Coordinates))
+	}
+
+	if c := feature.Geometry.Coordinates[0]; c[0] != line[0][1] || c[1] != line[0][0] {
+		t.Errorf("coordinates should be [lng, lat], got %v", c)
+	}
+
+	if v := feature.Properties["name"]; v != activity.Name {
+		t.Errorf("name property incorrect, got %v", v)
+	}
+
+	if v := feature.Properties["distance"]; v != activity.Distance {
+		t.Errorf("distance property incorrect, got %v", v)
+	}
+
+	if _, ok := feature.Properties["map"]; ok {
+		t.Error("map should not be a property")
+	}
+
+	if _, err := json.Marshal(feature); err != nil {
+		t.Errorf("feature should marshal, got %v", err)
+	}
+
+	// summary polyline is used if that is all there is
+	activity.Map.Polyline = ""
+	if l := len(activity.GeoJSON().Geometry.Coordinates); l != len(activity.Map.SummaryPolyline.Decode()) {
+		t.Errorf("should use summary polyline, got %d coordinates", l)
+	}
+
+	collection := ActivitiesGeoJSON([]*ActivitySummary{&activity.ActivitySummary, &activity.ActivitySummary})
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Errorf("feature collection incorrect, got %v", collection)
+	}
+}
+
+func TestActivityGeoJSONWithoutPolyline(t *testing.T) {
+	feature := (&ActivitySummary{Name: "Manual"}).GeoJSON()
+	if feature.Geometry != nil {
+		t.Errorf("geometry should be nil, got %v", feature.Geometry)
+	}
+
+	data, _ := json.Marshal(feature)
+	if !strings.Contains(string(data), `"geometry":null`) {
+		t.Errorf("geometry should be null, got %s", data)
+	}
+}
+
+func TestSegmentGeoJSON(t *testing.T) {
+	client := newCassetteClient(testToken, "segment_get")
+	segment, err := NewSegmentsService(client).Get(229781).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	feature := segment.GeoJSON()
+	if v := feature.Properties["name"]; v != "Hawk Hill" {
+		t.Errorf("name property incorrect, got %v", v)
+	}
+
+	if l := len(feature.Geometry.Coordinates); l != len(segment.Map.Polyline.Decode()) {
+		t.Errorf("incorrect number of coordinates, got %d", l)
+	}
+
+	if !strings.HasPrefix(segment.WKT(), "LINESTRING (-122.48") {
+		t.Errorf("wkt incorrect, got %v", segment.WKT())
+	}
+
+	kml := segment.KML()
+	if !strings.Contains(kml, "<name>Hawk Hill</name>") || !strings.Contains(kml, "<coordinates>-122.48") {
+		t.Errorf("kml incorrect, got %v", kml)
+	}
+
+	if c := SegmentsGeoJSON([]*SegmentDetailed{segment}); len(c.Features) != 1 {
+		t.Errorf("feature collection incorrect, got %v", c)
+	}
+
+	if kml := SegmentsKML([]*SegmentDetailed{segment, segment}); strings.Count(kml, "<name>Hawk Hill</name>") != 2 {
+		t.Errorf("kml should have a placemark for each segment, got %v", kml)
+	}
+}
+
+func TestSegmentExplorerGeoJSON(t *testing.T) {
+	client := newCassetteClient(testToken, "segment_explore")
+	segments, err := NewSegmentsService(client).Explore(37.674887, -122.595185, 37.840461, -122.280015).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	collection := SegmentExplorerGeoJSON(segments)
+	if len(collection.Features) != len(segments) {
+		t.Fatalf("incorrect number of features, got %d", len(collection.Features))
+	}
+
+	if _, ok := collection.Features[0].Properties["points"]; ok {
+		t.Error("points should not be a property")
+	}
+
+	if v := collection.Features[0].Properties["id"]; v != float64(segments[0].Id) {
+		t.Errorf("id property incorrect, got %v", v)
+	}
+
+	if kml := SegmentExplorerKML(segments); strings.Count(kml, "<Placemark>") != len(segments) {
+		t.Errorf("kml should have a placemark for each segment, got %v", kml)
+	}
+}
+
+func TestLocationStreamExport(t *testing.T) {
+	stream := &LocationStream{Data: [][2]float64{{0, 0}, {37.5, -122.25}, {37.75, -122.5}}}
+	stream.Type = StreamTypes.Location
+
+	if wkt := stream.WKT(); wkt != "LINESTRING (-122.25 37.5, -122.5 37.75)" {
+		t.Errorf("wkt incorrect, got %v", wkt)
+	}
+
+	if wkt := (&LocationStream{}).WKT(); wkt != "LINESTRING EMPTY" {
+		t.Errorf("empty wkt incorrect, got %v", wkt)
+	}
+
+	single := &LocationStream{Data: [][2]float64{{37.5, -122.25}}}
+	if wkt := single.WKT(); wkt != "LINESTRING EMPTY" {
+		t.Errorf("single point wkt incorrect, got %v", wkt)
+	}
+
+	if kml := single.KML(); strings.Contains(kml, "LineString") || !strings.Contains(kml, "<Placemark>") {
+		t.Errorf("single point kml should have no line, got %v", kml)
+	}
+
+	if kml := stream.KML(); !strings.Contains(kml, "<coordinates>-122.25,37.5 -122.5,37.75</coordinates>") {
+		t.Errorf("kml incorrect, got %v", kml)
+	}
+
+	feature := stream.GeoJSON()
+	if v := feature.Properties["type"]; v != "latlng" {
+		t.Errorf("type property incorrect, got %v", v)
+	}
+
+	a := &ActivitySummary{Name: "Fish & Chips <3"}
+	if kml := a.KML(); !strings.Contains(kml, "<name>Fish &amp; Chips &lt;3</name>") {
+		t.Errorf("kml name should be escaped, got %v", kml)
+	}
+}
+
+func TestGeoJSONEncoder(t *testing.T) {
+	stream := &LocationStream{Data: [][2]float64{{37.5, -122.25}, {37.75, -122.5}}}
+
+	var buf bytes.Buffer
+	e := NewGeoJSONEncoder(&buf)
+	e.Encode(stream.GeoJSON())
+	e.Encode(stream.GeoJSON())
+	e.Close()
+
+	var collection GeoJSONFeatureCollection
+	if err := json.Unmarshal(buf.Bytes(), &collection); err != nil {
+		t.Fatalf("encoded collection should be valid json: %v", err)
+	}
+
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 2 {
+		t.Errorf("encoded collection incorrect, got %v", buf.String())
+	}
+
+	if err := e.Encode(stream.GeoJSON()); err == nil {
+		t.Error("should return error after close")
+	}
+
+	buf.Reset()
+	NewGeoJSONEncoder(&buf).Close()
+	if s := buf.String(); s != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("empty collection incorrect, got %v", s)
+	}
+}
@@ -0,0 +1,158 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLocationDistance(t *testing.T) {
+	// Hawk Hill start to end, see segment_get cassette
+	start := Location{37.8331119, -122.4834356}
+	end := Location{37.8280722, -122.4981393}
+
+	if d := start.DistanceTo(end); math.Abs(d-1410) > 5 {
+		t.Errorf("haversine distance incorrect, got %v", d)
+	}
+
+	if d := start.VincentyDistanceTo(end); math.Abs(d-start.DistanceTo(end)) > 5 {
+		t.Errorf("vincenty distance incorrect, got %v", d)
+	}
+
+	// one degree of longitude on the equator
+	if d := (Location{0, 0}).VincentyDistanceTo(Location{0, 1}); math.Abs(d-111319.491) > 0.01 {
+		t.Errorf("vincenty distance incorrect, got %v", d)
+	}
+
+	if d := start.DistanceTo(start); d != 0 {
+		t.Errorf("distance to self should be 0, got %v", d)
+	}
+
+	if d := start.VincentyDistanceTo(start); d != 0 {
+		t.Errorf("distance to self should be 0, got %v", d)
+	}
+}
+
+func TestLocationBearing(t *testing.T) {
+	l := Location{37.0, -122.0}
+
+	if b := l.BearingTo(Location{38.0, -122.0}); math.Abs(b) > 1e-9 {
+		t.Errorf("bearing north incorrect, got %v", b)
+	}
+
+	if b := l.BearingTo(Location{37.0, -121.0}); math.Abs(b-90) > 1 {
+		t.Errorf("bearing east incorrect, got %v", b)
+	}
+
+	if b := l.BearingTo(Location{36.0, -122.0}); math.Abs(b-180) > 1e-9 {
+		t.Errorf("bearing south incorrect, got %v", b)
+	}
+
+	if b := l.BearingTo(Location{37.0, -123.0}); math.Abs(b-270) > 1 {
+		t.Errorf("bearing west incorrect, got %v", b)
+	}
+}
+
+func TestLocationIsNullIsland(t *testing.T) {
+	if !(Location{0, 0}).IsNullIsland() {
+		t.Error("[0, 0] should be null island")
+	}
+
+	if (Location{0, 1}).IsNullIsland() {
+		t.Error("[0, 1] should not be null island")
+	}
+}
+
+func TestLocationDistanceToLine(t *testing.T) {
+	line := [][2]float64{{37.0, -122.0}, {37.0, -121.99}, {37.01, -121.99}}
+
+	// about 111 meters north of the first line segment
+	if d := (Location{37.001, -121.995}).DistanceToLine(line); math.Abs(d-111.2) > 0.5 {
+		t.Errorf("distance to line incorrect, got %v", d)
+	}
+
+	// beyond the end of the line
+	l := Location{37.02, -121.99}
+	if d := l.DistanceToLine(line); math.Abs(d-l.DistanceTo(Location{37.01, -121.99})) > 0.5 {
+		t.Errorf("distance to line end incorrect, got %v", d)
+	}
+
+	if d := l.DistanceToLine(nil); !math.IsInf(d, 1) {
+		t.Errorf("distance to empty line should be infinite, got %v", d)
+	}
+}
+
+func TestBoundsAndCentroid(t *testing.T) {
+	line := [][2]float64{{0, 0}, {37.0, -122.0}, {37.0, -121.98}, {37.01, -121.98}}
+
+	b := NewBounds(line)
+	if b != (Bounds{37.0, -122.0, 37.01, -121.98}) {
+		t.Errorf("bounds incorrect, got %v", b)
+	}
+
+	if c := b.Center(); math.Abs(c[0]-37.005) > 1e-9 || math.Abs(c[1]+121.99) > 1e-9 {
+		t.Errorf("bounds center incorrect, got %v", c)
+	}
+
+	if !b.Contains(Location{37.005, -121.99}) || b.Contains(Location{37.02, -121.99}) {
+		t.Error("bounds contains incorrect")
+	}
+
+	if b := NewBounds(nil); b != (Bounds{}) {
+		t.Errorf("empty bounds incorrect, got %v", b)
+	}
+
+	// a straight line, the centroid is the middle
+	c := Centroid([][2]float64{{37.0, -122.0}, {37.0, -121.99}, {37.0, -121.98}})
+	if math.Abs(c[0]-37.0) > 1e-9 || math.Abs(c[1]+121.99) > 1e-9 {
+		t.Errorf("centroid incorrect, got %v", c)
+	}
+
+	if c := Centroid([][2]float64{{37.0, -122.0}}); c != (Location{37.0, -122.0}) {
+		t.Errorf("single point centroid incorrect, got %v", c)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	var encoded Polyline = "}g|eFnpqjVl@En@Md@HbAd@d@^h@Xx@VbARjBDh@OPQf@w@d@k@XKXDFPH\\EbGT`AV`@v@|@NTNb@?XOb@cAxAWLuE@eAFMBoAv@eBt@q@b@}@tAeAt@i@dAC`AFZj@dB?~@[h@MbAVn@b@b@\\d@Eh@Qb@_@d@eB|@c@h@WfBK|AMpA?VF\\\\t@f@t@h@j@|@b@hCb@b@XTd@Bl@GtA?jAL`ALp@Tr@RXd@Rx@Pn@^Zh@Tx@Zf@`@FTCzDy@f@Yx@m@n@Op@VJr@"
+	line := encoded.Decode()
+
+	for name, simplify := range map[string]func([][2]float64, float64) [][2]float64{
+		"douglas-peucker": SimplifyDouglasPeucker,
+		"visvalingam":     SimplifyVisvalingam,
+	} {
+		simplified := simplify(line, 10)
+		if len(simplified) >= len(line) || len(simplified) < 3 {
+			t.Errorf("%s should remove some points, got %d of %d", name, len(simplified), len(line))
+		}
+
+		if simplified[0] != line[0] || simplified[len(simplified)-1] != line[len(line)-1] {
+			t.Errorf("%s should keep end points", name)
+		}
+
+		if s := simplify(line, 0); len(s) != len(line) {
+			t.Errorf("%s with no tolerance should keep all points, got %d of %d", name, len(s), len(line))
+		}
+
+		if s := simplify(line[:2], 1000); len(s) != 2 {
+			t.Errorf("%s should keep short lines, got %d", name, len(s))
+		}
+	}
+
+	// douglas-peucker points should all be within tolerance of the simplified line
+	simplified := SimplifyDouglasPeucker(line, 10)
+	for i, p := range line {
+		if d := Location(p).DistanceToLine(simplified); d > 10.5 {
+			t.Errorf("point %d too far from simplified line, got %v", i, d)
+		}
+	}
+
+	// a straight line should be reduced to its end points
+	straight := [][2]float64{{37.0, -122.0}, {37.0, -121.99}, {37.0, -121.98}, {37.0, -121.97}}
+	if s := SimplifyVisvalingam(straight, 1); len(s) != 2 {
+		t.Errorf("straight line should simplify to 2 points, got %d", len(s))
+	}
+
+	if s := SimplifyDouglasPeucker(straight, 1); len(s) != 2 {
+		t.Errorf("straight line should simplify to 2 points, got %d", len(s))
+	}
+}
@@ -0,0 +1,343 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const heatmapTileSize = 256
+
+// maximum width or height of an image returned by Heatmap.Image
+const heatmapMaximumImageSize = 16384
+
+// A ColorRamp maps heatmap intensity, from low to high, to colors.
+// Pixels with no activity are transparent.
+type ColorRamp []color.RGBA
+
+var ColorRamps = struct {
+	Hot  ColorRamp
+	Blue ColorRamp
+	Gray ColorRamp
+}{
+	ColorRamp{{120, 0, 0, 255}, {220, 30, 0, 255}, {255, 140, 0, 255}, {255, 230, 60, 255}, {255, 255, 255, 255}},
+	ColorRamp{{0, 30, 120, 255}, {0, 110, 220, 255}, {80, 200, 255, 255}, {220, 250, 255, 255}},
+	ColorRamp{{60, 60, 60, 255}, {255, 255, 255, 255}},
+}
+
+// Heatmap accumulates line density from many activities in web mercator pixel space
+// and renders it as an image or as XYZ tiles. Each activity adds at most 1 to a pixel,
+// so the value of a pixel is the number of activities that passed through it.
+type Heatmap struct {
+	zoom          int
+	activityTypes map[ActivityType]bool
+	colorRamp     ColorRamp
+	counts        map[[2]int]float64
+}
+
+// NewHeatmap creates a heatmap accumulating at the given zoom level, 0-22.
+// Images and tiles can be rendered at this zoom or any lower one.
+func NewHeatmap(zoom int) *Heatmap {
+	if zoom < 0 {
+		zoom = 0
+	}
+
+	if zoom > 22 {
+		zoom = 22
+	}
+
+	return &Heatmap{
+		zoom:      zoom,
+		colorRamp: ColorRamps.Hot,
+		counts:    make(map[[2]int]float64),
+	}
+}
+
+// ActivityTypes limits the heatmap to the given types. Lines of other types are ignored.
+func (h *Heatmap) ActivityTypes(types ...ActivityType) *Heatmap {
+	h.activityTypes = make(map[ActivityType]bool)
+	for _, t := range types {
+		h.activityTypes[t] = true
+	}
+
+	return h
+}
+
+func (h *Heatmap) ColorRamp(ramp ColorRamp) *Heatmap {
+	if len(ramp) != 0 {
+		h.colorRamp = ramp
+	}
+
+	return h
+}
+
+// AddActivity adds the activity's route, the detailed Map.Polyline if available
+// otherwise the Map.SummaryPolyline. It is filtered by its Sport, or by its Type
+// if that was given to ActivityTypes, eg. ActivityTypes.Ride for a GravelRide.
+func (h *Heatmap) AddActivity(activity *ActivitySummary) {
+	activityType := activity.Sport()
+	if h.activityTypes[activity.Type] {
+		activityType = activity.Type
+	}
+
+	h.AddLine(activity.line(), activityType)
+}
+
+func (h *Heatmap) AddPolyline(polyline Polyline, activityType ActivityType) {
+	h.AddLine(polyline.Decode(), activityType)
+}
+
+// AddLocationStream adds the stream, Null Island points are skipped.
+func (h *Heatmap) AddLocationStream(stream *LocationStream, activityType ActivityType) {
+	h.AddLine(stream.line(), activityType)
+}
+
+// AddLine adds the line through the [lat, lng] points.
+func (h *Heatmap) AddLine(points [][2]float64, activityType ActivityType) {
+	if len(h.activityTypes) != 0 && !h.activityTypes[activityType] {
+		return
+	}
+
+	visited := make(map[[2]int]bool)
+	for i := range points {
+		x1, y1 := mercatorPixel(points[i], h.zoom)
+		x0, y0 := x1, y1
+		if i > 0 {
+			x0, y0 = mercatorPixel(points[i-1], h.zoom)
+		}
+
+		rasterizeLine(x0, y0, x1, y1, func(x, y int) {
+			p := [2]int{x, y}
+			if !visited[p] {
+				visited[p] = true
+				h.counts[p]++
+			}
+		})
+	}
+}
+
+// Image renders the area covered by all the lines at the given zoom level,
+// which must not be greater than the heatmap's zoom.
+func (h *Heatmap) Image(zoom int) (*image.RGBA, error) {
+	if zoom < 0 || zoom > h.zoom {
+		return nil, fmt.Errorf("zoom must be between 0 and %d", h.zoom)
+	}
+
+	counts := h.countsAt(zoom)
+	if len(counts) == 0 {
+		return nil, errors.New("heatmap is empty")
+	}
+
+	r := image.Rectangle{}
+	for p := range counts {
+		r = r.Union(image.Rect(p[0], p[1], p[0]+1, p[1]+1))
+	}
+
+	if r.Dx() > heatmapMaximumImageSize || r.Dy() > heatmapMaximumImageSize {
+		return nil, errors.New("heatmap image too large, use a lower zoom or tiles")
+	}
+
+	return h.render(counts, r, maximumCount(counts)), nil
+}
+
+// WritePNG renders the heatmap, see Image, and writes it to w as a PNG.
+func (h *Heatmap) WritePNG(w io.Writer, zoom int) error {
+	img, err := h.Image(zoom)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+// WriteTiles writes 256x256 XYZ web mercator tiles covering the lines to
+// directory/{z}/{x}/{y}.png for each zoom from minZoom to maxZoom. Empty tiles are not written.
+func (h *Heatmap) WriteTiles(directory string, minZoom, maxZoom int) error {
+	if minZoom < 0 || maxZoom > h.zoom || minZoom > maxZoom {
+		return fmt.Errorf("zooms must be between 0 and %d", h.zoom)
+	}
+
+	for z := minZoom; z <= maxZoom; z++ {
+		counts := h.countsAt(z)
+		max := maximumCount(counts)
+
+		tiles := make(map[[2]int]map[[2]int]float64)
+		for p, c := range counts {
+			t := [2]int{p[0] / heatmapTileSize, p[1] / heatmapTileSize}
+			if tiles[t] == nil {
+				tiles[t] = make(map[[2]int]float64)
+			}
+			tiles[t][p] = c
+		}
+
+		for t, tileCounts := range tiles {
+			r := image.Rect(t[0]*heatmapTileSize, t[1]*heatmapTileSize, (t[0]+1)*heatmapTileSize, (t[1]+1)*heatmapTileSize)
+			img := h.render(tileCounts, r, max)
+
+			dir := filepath.Join(directory, fmt.Sprint(z), fmt.Sprint(t[0]))
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+
+			if err := writePNGFile(filepath.Join(dir, fmt.Sprintf("%d.png", t[1])), img); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+/*********************************************************/
+
+// countsAt combines the accumulated counts into pixels at the given lower zoom,
+// keeping the highest count as the same activity may pass through several of the combined pixels.
+func (h *Heatmap) countsAt(zoom int) map[[2]int]float64 {
+	if zoom == h.zoom {
+		return h.counts
+	}
+
+	shift := uint(h.zoom - zoom)
+	counts := make(map[[2]int]float64)
+	for p, c := range h.counts {
+		q := [2]int{p[0] >> shift, p[1] >> shift}
+		counts[q] = math.Max(counts[q], c)
+	}
+
+	return counts
+}
+
+// render draws the pixels within r, normalizing intensity to max, the maximum of
+// all the counts at the zoom so tiles are colored consistently.
+func (h *Heatmap) render(counts map[[2]int]float64, r image.Rectangle, max float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	for p, c := range counts {
+		if !image.Pt(p[0], p[1]).In(r) {
+			continue
+		}
+
+		// log scale so a few very popular routes don't wash out everything else
+		intensity := 1.0
+		if max > 1 {
+			intensity = math.Log1p(c) / math.Log1p(max)
+		}
+
+		img.SetRGBA(p[0]-r.Min.X, p[1]-r.Min.Y, h.colorRamp.at(intensity))
+	}
+
+	return img
+}
+
+func maximumCount(counts map[[2]int]float64) float64 {
+	max := 0.0
+	for _, c := range counts {
+		max = math.Max(max, c)
+	}
+
+	return max
+}
+
+// at returns the color for the intensity, between 0 and 1.
+func (ramp ColorRamp) at(intensity float64) color.RGBA {
+	if len(ramp) == 1 || intensity <= 0 {
+		return ramp[0]
+	}
+
+	if intensity >= 1 {
+		return ramp[len(ramp)-1]
+	}
+
+	f := intensity * float64(len(ramp)-1)
+	i := int(f)
+	f -= float64(i)
+
+	a, b := ramp[i], ramp[i+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*f))
+	}
+
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
+
+// mercatorPixel returns the global web mercator pixel of the [lat, lng] point at the zoom.
+func mercatorPixel(point [2]float64, zoom int) (int, int) {
+	size := float64(heatmapTileSize) * math.Exp2(float64(zoom))
+	x, y := mercator(point)
+
+	max := int(size) - 1
+	return clampInt(int(math.Floor(x*size)), 0, max), clampInt(int(math.Floor(y*size)), 0, max)
+}
+
+// rasterizeLine calls plot for every pixel on the line using Bresenham's algorithm.
+func rasterizeLine(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx := absInt(x1 - x0)
+	dy := -absInt(y1 - y0)
+
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func writePNGFile(filename string, img image.Image) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err = png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+
+	if v > max {
+		return max
+	}
+
+	return v
+}
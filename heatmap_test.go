@@ -0,0 +1,146 @@
+package strava
+
+import (
+	"bytes"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHeatmapAddActivity(t *testing.T) {
+	line := EncodePolyline([][2]float64{{37.80, -122.45}, {37.80, -122.44}})
+
+	gravel := &ActivitySummary{Type: ActivityTypes.Ride, SportType: ActivityTypes.GravelRide}
+	gravel.Map.SummaryPolyline = line
+
+	h := NewHeatmap(12).ActivityTypes(ActivityTypes.GravelRide)
+	h.AddActivity(gravel)
+	if len(h.counts) == 0 {
+		t.Error("should match the sport type")
+	}
+
+	h = NewHeatmap(12).ActivityTypes(ActivityTypes.Ride)
+	h.AddActivity(gravel)
+	if len(h.counts) == 0 {
+		t.Error("should match the legacy type")
+	}
+
+	h = NewHeatmap(12).ActivityTypes(ActivityTypes.MountainBikeRide)
+	h.AddActivity(gravel)
+	if len(h.counts) != 0 {
+		t.Error("should not match other sports")
+	}
+}
+
+func TestHeatmapImage(t *testing.T) {
+	h := NewHeatmap(16).ActivityTypes(ActivityTypes.Ride)
+
+	line := [][2]float64{{37.80, -122.45}, {37.80, -122.44}, {37.81, -122.44}}
+	h.AddLine(line, ActivityTypes.Ride)
+	h.AddLine(line, ActivityTypes.Ride)
+	h.AddLine(line[:2], ActivityTypes.Ride)
+	h.AddLine([][2]float64{{37.70, -122.30}, {37.71, -122.31}}, ActivityTypes.Run)
+
+	img, err := h.Image(16)
+	if err != nil {
+		t.Fatalf("image error: %v", err)
+	}
+
+	// filtered run should not extend the image
+	b := img.Bounds()
+	if b.Dx() > 600 || b.Dy() > 600 {
+		t.Errorf("image too large, got %v", b)
+	}
+
+	x, y := mercatorPixel(line[0], 16)
+	minX, minY := mercatorPixel([2]float64{37.81, -122.45}, 16)
+	if c := img.RGBAAt(x-minX, y-minY); c != ColorRamps.Hot[len(ColorRamps.Hot)-1] {
+		t.Errorf("most visited pixel should be the hottest color, got %v", c)
+	}
+
+	x, y = mercatorPixel(line[2], 16)
+	if c := img.RGBAAt(x-minX, y-minY); c.A == 0 || c == ColorRamps.Hot[len(ColorRamps.Hot)-1] {
+		t.Errorf("less visited pixel should be a cooler color, got %v", c)
+	}
+
+	if c := img.RGBAAt(0, 0); c.A != 0 {
+		t.Errorf("pixel with no activity should be transparent, got %v", c)
+	}
+
+	var buf bytes.Buffer
+	if err := h.WritePNG(&buf, 12); err != nil {
+		t.Fatalf("png error: %v", err)
+	}
+
+	if _, err := png.Decode(&buf); err != nil {
+		t.Errorf("png should decode: %v", err)
+	}
+
+	if _, err := h.Image(17); err == nil {
+		t.Error("should return error for zoom greater than the heatmap's")
+	}
+
+	if _, err := NewHeatmap(10).Image(10); err == nil {
+		t.Error("should return error for empty heatmap")
+	}
+}
+
+func TestHeatmapTiles(t *testing.T) {
+	client := newCassetteClient(testToken, "segment_get")
+	segment, err := NewSegmentsService(client).Get(229781).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	h := NewHeatmap(14).ColorRamp(ColorRamps.Blue)
+	h.AddPolyline(segment.Map.Polyline, segment.ActivityType)
+
+	dir, err := ioutil.TempDir("", "heatmap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := h.WriteTiles(dir, 10, 14); err != nil {
+		t.Fatalf("tiles error: %v", err)
+	}
+
+	// Hawk Hill is in tile 10/163/395
+	f, err := os.Open(filepath.Join(dir, "10", "163", "395.png"))
+	if err != nil {
+		t.Fatalf("tile not written: %v", err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("tile should decode: %v", err)
+	}
+
+	if b := img.Bounds(); b.Dx() != 256 || b.Dy() != 256 {
+		t.Errorf("tile size incorrect, got %v", b)
+	}
+
+	tiles, _ := filepath.Glob(filepath.Join(dir, "14", "*", "*.png"))
+	if len(tiles) == 0 {
+		t.Error("tiles should be written at every zoom")
+	}
+
+	if err := h.WriteTiles(dir, 10, 15); err == nil {
+		t.Error("should return error for zoom greater than the heatmap's")
+	}
+}
+
+func TestColorRamp(t *testing.T) {
+	ramp := ColorRamp{{0, 0, 0, 255}, {200, 100, 50, 255}}
+
+	if c := ramp.at(0.5); c.R != 100 || c.G != 50 || c.B != 25 {
+		t.Errorf("color ramp interpolation incorrect, got %v", c)
+	}
+
+	if c := ramp.at(2); c != ramp[1] {
+		t.Errorf("color ramp should clamp, got %v", c)
+	}
+}
@@ -0,0 +1,366 @@
+package strava
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// the most efforts ListKOMs will return per page
+const komsPerPage = 200
+
+// A KOMSnapshot is an athlete's KOMs, and the top of their watched leaderboards, at a point in time.
+type KOMSnapshot struct {
+	AthleteId    int64                         `json:"athlete_id"`
+	Time         time.Time                     `json:"time"`
+	KOMs         []*SegmentEffortSummary       `json:"koms"`
+	Leaderboards map[int64]*SegmentLeaderboard `json:"leaderboards"` // by segment id
+}
+
+// KOMStore keeps the latest snapshot of each athlete between checks, and restarts.
+type KOMStore interface {
+	// Load returns nil, without an error, if there is no snapshot for the athlete.
+	Load(athleteId int64) (*KOMSnapshot, error)
+	Save(snapshot *KOMSnapshot) error
+}
+
+type KOMEventType string
+
+var KOMEventTypes = struct {
+	KOMGained   KOMEventType
+	KOMLost     KOMEventType
+	RankChanged KOMEventType
+}{"kom_gained", "kom_lost", "rank_changed"}
+
+type KOMEvent struct {
+	Type      KOMEventType
+	AthleteId int64
+	SegmentId int64
+	Time      time.Time
+
+	// Effort is the athlete's KOM effort that was gained or lost,
+	// or their KOM on the segment for RankChanged, if they have one.
+	Effort *SegmentEffortSummary
+
+	// Holder is the new leader of the segment for KOMLost,
+	// nil if the leaderboard could not be loaded.
+	Holder *SegmentLeaderboardEntry
+
+	// Ranks on watched leaderboards for RankChanged, 0 if not in the top entries.
+	PreviousRank int
+	Rank         int
+}
+
+// KOMWatcher compares snapshots of an athlete's KOMs, and of watched leaderboards,
+// with the previous snapshot in the store and calls back with what changed.
+// The first check for an athlete only records a snapshot.
+type KOMWatcher struct {
+	client     *Client
+	athleteId  int64
+	store      KOMStore
+	segmentIds []int64
+	entries    int
+	gender     Gender
+
+	onEvent []func(*KOMEvent)
+	onError []func(error)
+}
+
+func NewKOMWatcher(client *Client, athleteId int64, store KOMStore) *KOMWatcher {
+	return &KOMWatcher{
+		client:    client,
+		athleteId: athleteId,
+		store:     store,
+		entries:   10,
+	}
+}
+
+// WatchSegments adds leaderboards to snapshot for RankChanged events.
+func (w *KOMWatcher) WatchSegments(segmentIds ...int64) *KOMWatcher {
+	w.segmentIds = append(w.segmentIds, segmentIds...)
+	return w
+}
+
+// Entries sets how many of the top leaderboard entries are kept, defaults to 10.
+func (w *KOMWatcher) Entries(count int) *KOMWatcher {
+	w.entries = count
+	return w
+}
+
+// Gender filters the leaderboards, eg. Genders.Female to watch QOMs.
+func (w *KOMWatcher) Gender(gender Gender) *KOMWatcher {
+	w.gender = gender
+	return w
+}
+
+func (w *KOMWatcher) OnEvent(callback func(*KOMEvent)) *KOMWatcher {
+	w.onEvent = append(w.onEvent, callback)
+	return w
+}
+
+// OnError is called with errors from the checks made by Run.
+func (w *KOMWatcher) OnError(callback func(error)) *KOMWatcher {
+	w.onError = append(w.onError, callback)
+	return w
+}
+
+// Check takes a new snapshot, compares it with the previous one, saves it
+// and calls the event callbacks. The events are also returned.
+func (w *KOMWatcher) Check() ([]*KOMEvent, error) {
+	previous, err := w.store.Load(w.athleteId)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := w.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*KOMEvent, 0)
+	if previous != nil {
+		events = w.diff(previous, current)
+	}
+
+	if err := w.store.Save(current); err != nil {
+		return nil, err
+	}
+
+	for _, e := range events {
+		for _, callback := range w.onEvent {
+			callback(e)
+		}
+	}
+
+	return events, nil
+}
+
+// Run checks every interval until stop is closed. Errors are passed to the OnError callbacks.
+func (w *KOMWatcher) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(); err != nil {
+			for _, callback := range w.onError {
+				callback(err)
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/*********************************************************/
+
+func (w *KOMWatcher) snapshot() (*KOMSnapshot, error) {
+	snapshot := &KOMSnapshot{
+		AthleteId:    w.athleteId,
+		Time:         time.Now(),
+		KOMs:         make([]*SegmentEffortSummary, 0),
+		Leaderboards: make(map[int64]*SegmentLeaderboard),
+	}
+
+	athletes := NewAthletesService(w.client)
+	for page := 1; ; page++ {
+		koms, err := athletes.ListKOMs(w.athleteId).Page(page).PerPage(komsPerPage).Do()
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.KOMs = append(snapshot.KOMs, koms...)
+		if len(koms) < komsPerPage {
+			break
+		}
+	}
+
+	for _, id := range w.segmentIds {
+		leaderboard, err := w.leaderboard(id, w.entries)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Leaderboards[id] = leaderboard
+	}
+
+	return snapshot, nil
+}
+
+func (w *KOMWatcher) leaderboard(segmentId int64, entries int) (*SegmentLeaderboard, error) {
+	call := NewSegmentsService(w.client).GetLeaderboard(segmentId).PerPage(entries)
+	if w.gender != "" {
+		call.Gender(w.gender)
+	}
+
+	return call.Do()
+}
+
+func (w *KOMWatcher) diff(previous, current *KOMSnapshot) []*KOMEvent {
+	events := make([]*KOMEvent, 0)
+
+	before := komsBySegment(previous.KOMs)
+	after := komsBySegment(current.KOMs)
+
+	for _, effort := range current.KOMs {
+		if before[effort.Segment.Id] == nil {
+			events = append(events, w.event(KOMEventTypes.KOMGained, effort.Segment.Id, effort, current.Time))
+		}
+	}
+
+	for _, effort := range previous.KOMs {
+		if after[effort.Segment.Id] != nil {
+			continue
+		}
+
+		e := w.event(KOMEventTypes.KOMLost, effort.Segment.Id, effort, current.Time)
+
+		// the new holder, from the snapshot if the segment is watched.
+		// The event is still sent if the leaderboard can't be loaded.
+		leaderboard := current.Leaderboards[effort.Segment.Id]
+		if leaderboard == nil {
+			leaderboard, _ = w.leaderboard(effort.Segment.Id, 1)
+		}
+
+		if leaderboard != nil && len(leaderboard.Entries) != 0 {
+			e.Holder = leaderboard.Entries[0]
+		}
+
+		events = append(events, e)
+	}
+
+	for _, id := range w.segmentIds {
+		previousRank := leaderboardRank(previous.Leaderboards[id], w.athleteId)
+		rank := leaderboardRank(current.Leaderboards[id], w.athleteId)
+		if previousRank == rank {
+			continue
+		}
+
+		e := w.event(KOMEventTypes.RankChanged, id, after[id], current.Time)
+		e.PreviousRank = previousRank
+		e.Rank = rank
+
+		events = append(events, e)
+	}
+
+	return events
+}
+
+func (w *KOMWatcher) event(eventType KOMEventType, segmentId int64, effort *SegmentEffortSummary, t time.Time) *KOMEvent {
+	return &KOMEvent{
+		Type:      eventType,
+		AthleteId: w.athleteId,
+		SegmentId: segmentId,
+		Time:      t,
+		Effort:    effort,
+	}
+}
+
+func komsBySegment(efforts []*SegmentEffortSummary) map[int64]*SegmentEffortSummary {
+	koms := make(map[int64]*SegmentEffortSummary)
+	for _, e := range efforts {
+		koms[e.Segment.Id] = e
+	}
+
+	return koms
+}
+
+// leaderboardRank returns the athlete's rank on the leaderboard, 0 if not on it.
+func leaderboardRank(leaderboard *SegmentLeaderboard, athleteId int64) int {
+	if leaderboard == nil {
+		return 0
+	}
+
+	for _, entry := range leaderboard.Entries {
+		if entry.AthleteId == athleteId {
+			return entry.Rank
+		}
+	}
+
+	return 0
+}
+
+/*********************************************************/
+
+// MemoryKOMStore keeps snapshots in memory, they are lost when the program exits.
+type MemoryKOMStore struct {
+	lock      sync.Mutex
+	snapshots map[int64]*KOMSnapshot
+}
+
+func NewMemoryKOMStore() *MemoryKOMStore {
+	return &MemoryKOMStore{snapshots: make(map[int64]*KOMSnapshot)}
+}
+
+func (s *MemoryKOMStore) Load(athleteId int64) (*KOMSnapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.snapshots[athleteId], nil
+}
+
+func (s *MemoryKOMStore) Save(snapshot *KOMSnapshot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.snapshots[snapshot.AthleteId] = snapshot
+	return nil
+}
+
+// FileKOMStore keeps each athlete's snapshot as a json file in a directory, see MarshalVersioned.
+type FileKOMStore struct {
+	directory string
+}
+
+// NewFileKOMStore creates the directory if it does not exist.
+func NewFileKOMStore(directory string) (*FileKOMStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileKOMStore{directory}, nil
+}
+
+func (s *FileKOMStore) Load(athleteId int64) (*KOMSnapshot, error) {
+	data, err := ioutil.ReadFile(s.filename(athleteId))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot KOMSnapshot
+	_, err = UnmarshalVersioned(data, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+func (s *FileKOMStore) Save(snapshot *KOMSnapshot) error {
+	data, err := MarshalVersioned(snapshot)
+	if err != nil {
+		return err
+	}
+
+	// write then rename so a crash can't leave a partial snapshot
+	filename := s.filename(snapshot.AthleteId)
+	if err := ioutil.WriteFile(filename+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(filename+".tmp", filename)
+}
+
+func (s *FileKOMStore) filename(athleteId int64) string {
+	return filepath.Join(s.directory, fmt.Sprintf("koms_%d.json", athleteId))
+}
@@ -0,0 +1,176 @@
+package strava
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+// pathTransport responds with the body set for the request path, or a 404
+type pathTransport struct {
+	http.Transport
+	bodies   map[string]string
+	requests []*http.Request
+}
+
+func (t *pathTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+
+	body, ok := t.bodies[req.URL.Path]
+	status := 200
+	if !ok {
+		body, status = `{"message":"Record Not Found"}`, 404
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+func newPathClient(bodies map[string]string) (*Client, *pathTransport) {
+	transport := &pathTransport{bodies: bodies}
+
+	c := NewClient("")
+	c.httpClient = &http.Client{Transport: transport}
+
+	return c, transport
+}
+
+func testKOMsJSON(segmentIds ...int64) string {
+	efforts := make([]*SegmentEffortSummary, 0)
+	for _, id := range segmentIds {
+		e := &SegmentEffortSummary{}
+		e.Id = id * 10
+		e.Segment.Id = id
+		efforts = append(efforts, e)
+	}
+
+	data, _ := json.Marshal(efforts)
+	return string(data)
+}
+
+func testLeaderboardJSON(athleteIds ...int64) string {
+	leaderboard := &SegmentLeaderboard{EntryCount: 100}
+	for i, id := range athleteIds {
+		leaderboard.Entries = append(leaderboard.Entries, &SegmentLeaderboardEntry{AthleteId: id, Rank: i + 1, ElapsedTime: 300 + i})
+	}
+
+	data, _ := json.Marshal(leaderboard)
+	return string(data)
+}
+
+func TestKOMWatcher(t *testing.T) {
+	client, transport := newPathClient(map[string]string{
+		"/api/v3/athletes/1/koms":         testKOMsJSON(10, 20),
+		"/api/v3/segments/30/leaderboard": testLeaderboardJSON(5, 1, 6),
+		"/api/v3/segments/20/leaderboard": testLeaderboardJSON(7, 1),
+	})
+
+	events := make([]*KOMEvent, 0)
+	watcher := NewKOMWatcher(client, 1, NewMemoryKOMStore()).
+		WatchSegments(30).
+		Gender(Genders.Female).
+		OnEvent(func(e *KOMEvent) { events = append(events, e) })
+
+	// the first check only records
+	if _, err := watcher.Check(); err != nil {
+		t.Fatalf("check error: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("first check should have no events, got %d", len(events))
+	}
+
+	req := transport.requests[len(transport.requests)-1]
+	if req.URL.Path != "/api/v3/segments/30/leaderboard" || req.URL.RawQuery != "gender=F&per_page=10" {
+		t.Errorf("leaderboard request incorrect, got %v %v", req.URL.Path, req.URL.RawQuery)
+	}
+
+	// lost 20 to athlete 7, gained 30 and moved up to first on it
+	transport.bodies["/api/v3/athletes/1/koms"] = testKOMsJSON(10, 30)
+	transport.bodies["/api/v3/segments/30/leaderboard"] = testLeaderboardJSON(1, 5, 6)
+
+	returned, err := watcher.Check()
+	if err != nil {
+		t.Fatalf("check error: %v", err)
+	}
+
+	if len(events) != 3 || len(returned) != 3 {
+		t.Fatalf("incorrect number of events, got %d", len(events))
+	}
+
+	if e := events[0]; e.Type != KOMEventTypes.KOMGained || e.SegmentId != 30 || e.Effort.Id != 300 || e.AthleteId != 1 {
+		t.Errorf("gained event incorrect, got %v", e)
+	}
+
+	if e := events[1]; e.Type != KOMEventTypes.KOMLost || e.SegmentId != 20 || e.Holder == nil || e.Holder.AthleteId != 7 {
+		t.Errorf("lost event incorrect, got %v", e)
+	}
+
+	if e := events[2]; e.Type != KOMEventTypes.RankChanged || e.SegmentId != 30 || e.PreviousRank != 2 || e.Rank != 1 || e.Effort.Id != 300 {
+		t.Errorf("rank changed event incorrect, got %v", e)
+	}
+
+	// nothing changed
+	events = events[:0]
+	if _, err := watcher.Check(); err != nil {
+		t.Fatalf("check error: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("should have no events, got %d", len(events))
+	}
+
+	// lost 10, the leaderboard can't be loaded
+	events = events[:0]
+	transport.bodies["/api/v3/athletes/1/koms"] = testKOMsJSON(30)
+	if _, err := watcher.Check(); err != nil {
+		t.Fatalf("check error: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Type != KOMEventTypes.KOMLost || events[0].SegmentId != 10 || events[0].Holder != nil {
+		t.Errorf("lost event should have no holder, got %v", events)
+	}
+
+	// errors
+	delete(transport.bodies, "/api/v3/athletes/1/koms")
+	if _, err := watcher.Check(); err == nil {
+		t.Error("should return an error")
+	}
+}
+
+func TestFileKOMStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "koms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileKOMStore(dir)
+	if err != nil {
+		t.Fatalf("store error: %v", err)
+	}
+
+	snapshot, err := store.Load(1)
+	if snapshot != nil || err != nil {
+		t.Errorf("should have no snapshot, got %v %v", snapshot, err)
+	}
+
+	json.Unmarshal([]byte(`{"athlete_id":1,"koms":`+testKOMsJSON(10)+`,"leaderboards":{"30":`+testLeaderboardJSON(5, 1)+`}}`), &snapshot)
+	if err := store.Save(snapshot); err != nil {
+		t.Fatalf("save error: %v", err)
+	}
+
+	loaded, err := store.Load(1)
+	if err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+
+	if len(loaded.KOMs) != 1 || loaded.KOMs[0].Segment.Id != 10 || leaderboardRank(loaded.Leaderboards[30], 1) != 2 {
+		t.Errorf("snapshot not saved, got %v", loaded)
+	}
+}
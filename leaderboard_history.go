@@ -0,0 +1,330 @@
+package strava
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LeaderboardFilter is a combination of the GetLeaderboard filters.
+// Snapshots of each combination are kept separately.
+type LeaderboardFilter struct {
+	Gender      Gender      `json:"gender,omitempty"`
+	AgeGroup    AgeGroup    `json:"age_group,omitempty"`
+	WeightClass WeightClass `json:"weight_class,omitempty"`
+	ClubId      int64       `json:"club_id,omitempty"`
+	Following   bool        `json:"following,omitempty"`
+	DateRange   DateRange   `json:"date_range,omitempty"`
+}
+
+// A LeaderboardSnapshot is a leaderboard as it was at a point in time.
+type LeaderboardSnapshot struct {
+	SegmentId   int64               `json:"segment_id"`
+	Filter      LeaderboardFilter   `json:"filter"`
+	Time        time.Time           `json:"time"`
+	Leaderboard *SegmentLeaderboard `json:"leaderboard"`
+}
+
+// LeaderboardStore keeps the snapshots recorded by LeaderboardHistory.
+type LeaderboardStore interface {
+	Add(snapshot *LeaderboardSnapshot) error
+
+	// List returns the snapshots of the segment with exactly the filter, oldest first.
+	List(segmentId int64, filter LeaderboardFilter) ([]*LeaderboardSnapshot, error)
+}
+
+// A LeaderboardRank is an athlete's rank in a snapshot.
+type LeaderboardRank struct {
+	Time time.Time
+
+	// Rank and Entry are 0 and nil if the athlete was not on the leaderboard.
+	Rank  int
+	Entry *SegmentLeaderboardEntry
+}
+
+// LeaderboardHistory records leaderboards over time and answers questions about how they changed.
+type LeaderboardHistory struct {
+	client  *Client
+	store   LeaderboardStore
+	entries int
+}
+
+func NewLeaderboardHistory(client *Client, store LeaderboardStore) *LeaderboardHistory {
+	return &LeaderboardHistory{
+		client: client,
+		store:  store,
+	}
+}
+
+// Entries limits how many of the top entries are recorded, defaults to 0 for the whole leaderboard.
+// Popular segments have tens of thousands of entries, each 200 take a request.
+func (h *LeaderboardHistory) Entries(count int) *LeaderboardHistory {
+	h.entries = count
+	return h
+}
+
+// Record loads the current leaderboard of the segment for the filter and adds it to the store.
+func (h *LeaderboardHistory) Record(segmentId int64, filter LeaderboardFilter) (*LeaderboardSnapshot, error) {
+	call := NewSegmentsService(h.client).GetLeaderboard(segmentId)
+	for k, v := range filter.params() {
+		call.ops[k] = v
+	}
+
+	leaderboard, err := call.doPages(h.entries)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &LeaderboardSnapshot{
+		SegmentId:   segmentId,
+		Filter:      filter,
+		Time:        time.Now(),
+		Leaderboard: leaderboard,
+	}
+
+	if err := h.store.Add(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Entered returns the entries of the latest snapshot in the top places that were not there
+// in the latest snapshot before since, eg. who entered the top 10 this week.
+// If there is no snapshot before since the earliest one is used.
+func (h *LeaderboardHistory) Entered(segmentId int64, filter LeaderboardFilter, top int, since time.Time) ([]*SegmentLeaderboardEntry, error) {
+	snapshots, err := h.store.List(segmentId, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshots) == 0 {
+		return make([]*SegmentLeaderboardEntry, 0), nil
+	}
+
+	before := snapshots[0]
+	for _, s := range snapshots {
+		if s.Time.After(since) {
+			break
+		}
+		before = s
+	}
+
+	entered, _ := DiffLeaderboards(before.Leaderboard, snapshots[len(snapshots)-1].Leaderboard, top)
+	return entered, nil
+}
+
+// RankHistory returns the athlete's rank in every snapshot, oldest first.
+func (h *LeaderboardHistory) RankHistory(segmentId int64, filter LeaderboardFilter, athleteId int64) ([]*LeaderboardRank, error) {
+	snapshots, err := h.store.List(segmentId, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make([]*LeaderboardRank, 0, len(snapshots))
+	for _, s := range snapshots {
+		r := &LeaderboardRank{Time: s.Time}
+		for _, entry := range s.Leaderboard.Entries {
+			if entry.AthleteId == athleteId {
+				r.Rank, r.Entry = entry.Rank, entry
+				break
+			}
+		}
+
+		ranks = append(ranks, r)
+	}
+
+	return ranks, nil
+}
+
+// DiffLeaderboards returns the athletes that entered and left the top places between the leaderboards.
+// Entered entries are from after, left entries from before. A top of 0 compares all the entries.
+func DiffLeaderboards(before, after *SegmentLeaderboard, top int) ([]*SegmentLeaderboardEntry, []*SegmentLeaderboardEntry) {
+	inBefore := topAthletes(before, top)
+	inAfter := topAthletes(after, top)
+
+	entered := make([]*SegmentLeaderboardEntry, 0)
+	for _, entry := range after.Entries {
+		if (top == 0 || entry.Rank <= top) && !inBefore[entry.AthleteId] {
+			entered = append(entered, entry)
+		}
+	}
+
+	left := make([]*SegmentLeaderboardEntry, 0)
+	for _, entry := range before.Entries {
+		if (top == 0 || entry.Rank <= top) && !inAfter[entry.AthleteId] {
+			left = append(left, entry)
+		}
+	}
+
+	return entered, left
+}
+
+/*********************************************************/
+
+// params are the GetLeaderboard parameters of the filter.
+func (f LeaderboardFilter) params() map[string]interface{} {
+	params := make(map[string]interface{})
+
+	if f.Gender != "" {
+		params["gender"] = f.Gender
+	}
+
+	if f.AgeGroup != "" {
+		params["age_group"] = f.AgeGroup
+	}
+
+	if f.WeightClass != "" {
+		params["weight_class"] = f.WeightClass
+	}
+
+	if f.ClubId != 0 {
+		params["club_id"] = f.ClubId
+	}
+
+	if f.Following {
+		params["following"] = true
+	}
+
+	if f.DateRange != "" {
+		params["date_range"] = f.DateRange
+	}
+
+	return params
+}
+
+// key uniquely identifies the filter, "all" if there is no filtering.
+func (f LeaderboardFilter) key() string {
+	values := make(url.Values)
+	for k, v := range f.params() {
+		values.Set(k, fmt.Sprintf("%v", v))
+	}
+
+	if len(values) == 0 {
+		return "all"
+	}
+
+	return values.Encode()
+}
+
+func topAthletes(leaderboard *SegmentLeaderboard, top int) map[int64]bool {
+	athletes := make(map[int64]bool)
+	for _, entry := range leaderboard.Entries {
+		if top == 0 || entry.Rank <= top {
+			athletes[entry.AthleteId] = true
+		}
+	}
+
+	return athletes
+}
+
+/*********************************************************/
+
+// MemoryLeaderboardStore keeps snapshots in memory, they are lost when the program exits.
+type MemoryLeaderboardStore struct {
+	lock      sync.Mutex
+	snapshots map[string][]*LeaderboardSnapshot
+}
+
+func NewMemoryLeaderboardStore() *MemoryLeaderboardStore {
+	return &MemoryLeaderboardStore{snapshots: make(map[string][]*LeaderboardSnapshot)}
+}
+
+func (s *MemoryLeaderboardStore) Add(snapshot *LeaderboardSnapshot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := fmt.Sprintf("%d/%s", snapshot.SegmentId, snapshot.Filter.key())
+	s.snapshots[key] = append(s.snapshots[key], snapshot)
+
+	return nil
+}
+
+func (s *MemoryLeaderboardStore) List(segmentId int64, filter LeaderboardFilter) ([]*LeaderboardSnapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshots := s.snapshots[fmt.Sprintf("%d/%s", segmentId, filter.key())]
+	list := make([]*LeaderboardSnapshot, len(snapshots))
+	copy(list, snapshots)
+
+	return list, nil
+}
+
+// FileLeaderboardStore appends the snapshots of each segment and filter
+// to a file in a directory, one json object per line, see MarshalVersioned.
+type FileLeaderboardStore struct {
+	lock      sync.Mutex
+	directory string
+}
+
+// NewFileLeaderboardStore creates the directory if it does not exist.
+func NewFileLeaderboardStore(directory string) (*FileLeaderboardStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FileLeaderboardStore{directory: directory}, nil
+}
+
+func (s *FileLeaderboardStore) Add(snapshot *LeaderboardSnapshot) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	data, err := MarshalVersioned(snapshot)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.filename(snapshot.SegmentId, snapshot.Filter), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (s *FileLeaderboardStore) List(segmentId int64, filter LeaderboardFilter) ([]*LeaderboardSnapshot, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	snapshots := make([]*LeaderboardSnapshot, 0)
+
+	f, err := os.Open(s.filename(segmentId, filter))
+	if os.IsNotExist(err) {
+		return snapshots, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+	for scanner.Scan() {
+		var snapshot LeaderboardSnapshot
+		if _, err := UnmarshalVersioned(scanner.Bytes(), &snapshot); err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, &snapshot)
+	}
+
+	return snapshots, scanner.Err()
+}
+
+func (s *FileLeaderboardStore) filename(segmentId int64, filter LeaderboardFilter) string {
+	key := strings.NewReplacer("&", ",", "=", "-").Replace(filter.key())
+	return filepath.Join(s.directory, fmt.Sprintf("leaderboard_%d_%s.jsonl", segmentId, key))
+}
@@ -0,0 +1,220 @@
+package strava
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pagedLeaderboardTransport returns a leaderboard of count athletes, ids 1 to count, in pages.
+// Like Strava, 2 context entries are added to later pages unless context_entries is 0, or always if context is set.
+type pagedLeaderboardTransport struct {
+	http.Transport
+	count    int
+	context  bool
+	requests []*http.Request
+}
+
+func (t *pagedLeaderboardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+
+	var page, perPage int
+	json.Unmarshal([]byte(req.URL.Query().Get("page")), &page)
+	json.Unmarshal([]byte(req.URL.Query().Get("per_page")), &perPage)
+
+	leaderboard := &SegmentLeaderboard{EntryCount: t.count}
+	for i := (page - 1) * perPage; i < page*perPage && i < t.count; i++ {
+		leaderboard.Entries = append(leaderboard.Entries, &SegmentLeaderboardEntry{AthleteId: int64(i + 1), Rank: i + 1})
+	}
+
+	if (t.context || req.URL.Query().Get("context_entries") != "0") && page > 1 {
+		for i := 0; i < 2 && i < t.count; i++ {
+			leaderboard.Entries = append(leaderboard.Entries, &SegmentLeaderboardEntry{AthleteId: int64(i + 1), Rank: i + 1})
+		}
+	}
+
+	data, _ := json.Marshal(leaderboard)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(string(data))),
+	}, nil
+}
+
+func newTestLeaderboard(athleteIds ...int64) *SegmentLeaderboard {
+	leaderboard := &SegmentLeaderboard{EntryCount: len(athleteIds)}
+	for i, id := range athleteIds {
+		leaderboard.Entries = append(leaderboard.Entries, &SegmentLeaderboardEntry{AthleteId: id, Rank: i + 1})
+	}
+
+	return leaderboard
+}
+
+func TestSegmentsGetLeaderboardDoAll(t *testing.T) {
+	transport := &pagedLeaderboardTransport{count: 450}
+	client := NewClient("")
+	client.httpClient = &http.Client{Transport: transport}
+
+	leaderboard, err := NewSegmentsService(client).GetLeaderboard(229781).Gender(Genders.Female).ContextEntries(2).DoAll()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if leaderboard.EntryCount != 450 || len(leaderboard.Entries) != 450 {
+		t.Errorf("should load every entry, got %d", len(leaderboard.Entries))
+	}
+
+	if len(transport.requests) != 3 {
+		t.Fatalf("should request 3 pages, got %d", len(transport.requests))
+	}
+
+	if q := transport.requests[2].URL.RawQuery; q != "context_entries=0&gender=F&page=3&per_page=200" {
+		t.Errorf("request query incorrect, got %v", q)
+	}
+
+	// context entries repeated on each page
+	client.httpClient = &http.Client{Transport: &pagedLeaderboardTransport{count: 450, context: true}}
+
+	leaderboard, err = NewSegmentsService(client).GetLeaderboard(229781).DoAll()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(leaderboard.Entries) != 450 {
+		t.Errorf("should not repeat entries, got %d", len(leaderboard.Entries))
+	}
+
+	// errors
+	_, err = NewSegmentsService(NewStubResponseClient("bad json")).GetLeaderboard(123).DoAll()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+}
+
+func TestLeaderboardHistory(t *testing.T) {
+	transport := &pagedLeaderboardTransport{count: 450}
+	client := NewClient("")
+	client.httpClient = &http.Client{Transport: transport}
+
+	store := NewMemoryLeaderboardStore()
+	history := NewLeaderboardHistory(client, store).Entries(250)
+	filter := LeaderboardFilter{AgeGroup: AgeGroups.From25to34, ClubId: 7}
+
+	snapshot, err := history.Record(229781, filter)
+	if err != nil {
+		t.Fatalf("record error: %v", err)
+	}
+
+	if len(snapshot.Leaderboard.Entries) != 250 || len(transport.requests) != 2 {
+		t.Errorf("should load only the top entries, got %d", len(snapshot.Leaderboard.Entries))
+	}
+
+	if q := transport.requests[0].URL.RawQuery; q != "age_group=25_34&club_id=7&context_entries=0&page=1&per_page=200" {
+		t.Errorf("request query incorrect, got %v", q)
+	}
+
+	// other filters are kept separately
+	if list, _ := store.List(229781, LeaderboardFilter{}); len(list) != 0 {
+		t.Errorf("should have no overall snapshots, got %d", len(list))
+	}
+
+	// some history
+	store = NewMemoryLeaderboardStore()
+	history = NewLeaderboardHistory(client, store)
+
+	now := time.Now()
+	store.Add(&LeaderboardSnapshot{229781, filter, now.Add(-14 * 24 * time.Hour), newTestLeaderboard(1, 2, 3, 4)})
+	store.Add(&LeaderboardSnapshot{229781, filter, now.Add(-8 * 24 * time.Hour), newTestLeaderboard(2, 1, 3, 4)})
+	store.Add(&LeaderboardSnapshot{229781, filter, now.Add(-1 * 24 * time.Hour), newTestLeaderboard(5, 2, 4, 1)})
+
+	entered, err := history.Entered(229781, filter, 3, now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("entered error: %v", err)
+	}
+
+	if len(entered) != 2 || entered[0].AthleteId != 5 || entered[1].AthleteId != 4 {
+		t.Errorf("entered incorrect, got %v", entered)
+	}
+
+	ranks, err := history.RankHistory(229781, filter, 1)
+	if err != nil {
+		t.Fatalf("rank history error: %v", err)
+	}
+
+	expected := []int{1, 2, 4}
+	for i, r := range ranks {
+		if r.Rank != expected[i] {
+			t.Errorf("rank %d incorrect, got %d", i, r.Rank)
+		}
+	}
+
+	ranks, _ = history.RankHistory(229781, filter, 5)
+	if ranks[0].Rank != 0 || ranks[0].Entry != nil || ranks[2].Rank != 1 {
+		t.Errorf("rank history incorrect, got %v", ranks)
+	}
+}
+
+func mustList(t *testing.T, store LeaderboardStore, filter LeaderboardFilter) []*LeaderboardSnapshot {
+	list, err := store.List(229781, filter)
+	if err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+
+	return list
+}
+
+func TestDiffLeaderboards(t *testing.T) {
+	entered, left := DiffLeaderboards(newTestLeaderboard(1, 2, 3), newTestLeaderboard(3, 4, 1, 2), 2)
+
+	if len(entered) != 2 || entered[0].AthleteId != 3 || entered[1].AthleteId != 4 {
+		t.Errorf("entered incorrect, got %v", entered)
+	}
+
+	if len(left) != 2 || left[0].AthleteId != 1 || left[1].AthleteId != 2 {
+		t.Errorf("left incorrect, got %v", left)
+	}
+
+	entered, left = DiffLeaderboards(newTestLeaderboard(1, 2, 3), newTestLeaderboard(3, 4, 1, 2), 0)
+	if len(entered) != 1 || len(left) != 0 {
+		t.Errorf("diff incorrect, got %d entered %d left", len(entered), len(left))
+	}
+}
+
+func TestFileLeaderboardStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "leaderboards")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileLeaderboardStore(dir)
+	if err != nil {
+		t.Fatalf("store error: %v", err)
+	}
+
+	filter := LeaderboardFilter{Gender: Genders.Male, Following: true}
+	if list := mustList(t, store, filter); len(list) != 0 {
+		t.Errorf("should have no snapshots, got %d", len(list))
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	store.Add(&LeaderboardSnapshot{229781, filter, now, newTestLeaderboard(1, 2)})
+	store.Add(&LeaderboardSnapshot{229781, filter, now.Add(time.Hour), newTestLeaderboard(2, 1)})
+	store.Add(&LeaderboardSnapshot{229781, LeaderboardFilter{}, now, newTestLeaderboard(3)})
+
+	list := mustList(t, store, filter)
+	if len(list) != 2 {
+		t.Fatalf("incorrect number of snapshots, got %d", len(list))
+	}
+
+	if !list[0].Time.Equal(now) || list[1].Leaderboard.Entries[0].AthleteId != 2 || list[1].Filter != filter {
+		t.Errorf("snapshot not loaded, got %v", list[1])
+	}
+
+	if list := mustList(t, store, LeaderboardFilter{}); len(list) != 1 {
+		t.Errorf("incorrect number of snapshots, got %d", len(list))
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // An OAuthAuthenticator holds state about how OAuth requests should be authenticated.
@@ -19,6 +20,10 @@ type OAuthAuthenticator struct {
 	// can be used to create a client using the incoming request, for Example:
 	//    func(r *http.Request) { return urlfetch.Client(appengine.NewContext(r)) }
 	RequestClientGenerator func(r *http.Request) *http.Client
+
+	// BaseURL is where the token exchange and authorization page are, eg. the URL of a
+	// stravatest.Server. Defaults to https://www.strava.com/api/v3, see Client.SetBaseURL.
+	BaseURL string
 }
 
 // Permission represents the access of an access_token.
@@ -73,7 +78,7 @@ func (auth OAuthAuthenticator) Authorize(code string, client *http.Client) (*Aut
 		client = http.DefaultClient
 	}
 
-	resp, err := client.PostForm(basePath+"/oauth/token",
+	resp, err := client.PostForm(auth.url("/oauth/token"),
 		url.Values{"client_id": {fmt.Sprintf("%d", ClientId)}, "client_secret": {ClientSecret}, "code": {code}})
 
 	// this was a poor request, maybe strava servers down?
@@ -153,7 +158,7 @@ func (auth OAuthAuthenticator) HandlerFunc(
 
 // AuthorizationURL constructs the url a user should use to authorize this specific application.
 func (auth OAuthAuthenticator) AuthorizationURL(state string, scope Permission, force bool) string {
-	path := fmt.Sprintf("%s/oauth/authorize?client_id=%d&response_type=code&redirect_uri=%s&scope=%v", basePath, ClientId, auth.CallbackURL, scope)
+	path := fmt.Sprintf("%s?client_id=%d&response_type=code&redirect_uri=%s&scope=%v", auth.url("/oauth/authorize"), ClientId, auth.CallbackURL, scope)
 
 	if state != "" {
 		path += "&state=" + state
@@ -166,6 +171,14 @@ func (auth OAuthAuthenticator) AuthorizationURL(state string, scope Permission,
 	return path
 }
 
+func (auth OAuthAuthenticator) url(path string) string {
+	if auth.BaseURL == "" {
+		return basePath + path
+	}
+
+	return strings.TrimSuffix(auth.BaseURL, "/") + path
+}
+
 /*********************************************************/
 
 type OAuthService struct {
@@ -234,6 +234,22 @@ func TestOAuthAuthenticatorAuthorizationURL(t *testing.T) {
 	if url != basePath+"/oauth/authorize?client_id=0&response_type=code&redirect_uri=http://abc.com/strava/oauth&scope=public" {
 		t.Errorf("incorrect oauth url, got %v", url)
 	}
+
+	auth.BaseURL = "http://127.0.0.1:8080/api/v3/"
+	url = auth.AuthorizationURL("", Permissions.Public, false)
+	if url != "http://127.0.0.1:8080/api/v3/oauth/authorize?client_id=0&response_type=code&redirect_uri=http://abc.com/strava/oauth&scope=public" {
+		t.Errorf("should use the base url, got %v", url)
+	}
+}
+
+func TestOAuthAuthenticatorBaseURL(t *testing.T) {
+	transport := &storeRequestTransport{}
+	auth := OAuthAuthenticator{BaseURL: "http://127.0.0.1:8080/api/v3"}
+
+	auth.Authorize("75e251e3ff8fff", &http.Client{Transport: transport})
+	if u := transport.request.URL.String(); u != "http://127.0.0.1:8080/api/v3/oauth/token" {
+		t.Errorf("should exchange the token at the base url, got %v", u)
+	}
 }
 
 func TestOAuthErrorError(t *testing.T) {
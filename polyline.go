@@ -1,48 +1,197 @@
 package strava
 
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+)
+
 type Polyline string
 
+// PolylineMap is the map of an activity, route or segment.
+// Segments don't have a SummaryPolyline.
+type PolylineMap struct {
+	Id              string   `json:"id"`
+	Polyline        Polyline `json:"polyline"`
+	SummaryPolyline Polyline `json:"summary_polyline,omitempty"`
+}
+
+// MarshalJSON keeps an empty summary polyline for activity maps, with ids like "a123",
+// as Strava always sends one for activities, and omits it for segments like Strava.
+func (m PolylineMap) MarshalJSON() ([]byte, error) {
+	type polylineMap PolylineMap
+
+	if !strings.HasPrefix(m.Id, "a") {
+		return json.Marshal(polylineMap(m))
+	}
+
+	return json.Marshal(struct {
+		polylineMap
+		SummaryPolyline Polyline `json:"summary_polyline"`
+	}{polylineMap(m), m.SummaryPolyline})
+}
+
+// PolylinePrecision is the number of decimal places kept by the encoding.
+// Strava uses 5, some other services use 6.
+type PolylinePrecision int
+
+var PolylinePrecisions = struct {
+	Five PolylinePrecision
+	Six  PolylinePrecision
+}{5, 6}
+
+func (p PolylinePrecision) factor() float64 {
+	return math.Pow(10, float64(p))
+}
+
+func polylinePrecision(precision []PolylinePrecision) PolylinePrecision {
+	if len(precision) != 0 && precision[0] > 0 {
+		return precision[0]
+	}
+
+	return PolylinePrecisions.Five
+}
+
+// EncodePolyline converts the [lat, lng] points into a polyline
+// in standard Google polyline encoding. The precision defaults to 5.
+func EncodePolyline(points [][2]float64, precision ...PolylinePrecision) Polyline {
+	factor := polylinePrecision(precision).factor()
+
+	var buf bytes.Buffer
+	var last [2]int
+	for _, p := range points {
+		for k := 0; k < 2; k++ {
+			v := int(math.Round(p[k] * factor))
+			encodePolylineValue(&buf, v-last[k])
+			last[k] = v
+		}
+	}
+
+	return Polyline(buf.String())
+}
+
+func encodePolylineValue(buf *bytes.Buffer, v int) {
+	v <<= 1
+	if v < 0 {
+		v = ^v
+	}
+
+	for v >= 0x20 {
+		buf.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	buf.WriteByte(byte(v + 63))
+}
+
 // Decode will take the polyline which is a string
 // in standard Google polyline encoding and convert it to an array.
-func (p Polyline) Decode() [][2]float64 {
-	var count, index int
-	factor := 1.0e5
+// Malformed polylines are decoded up to the first error, use DecodeE to check for errors.
+func (p Polyline) Decode(precision ...PolylinePrecision) [][2]float64 {
+	line, _ := p.DecodeE(precision...)
+	return line
+}
+
+// DecodeE is like Decode but returns a *PolylineError if the polyline is malformed,
+// along with the points decoded before the problem.
+func (p Polyline) DecodeE(precision ...PolylinePrecision) ([][2]float64, error) {
+	d := NewPolylineDecoder(bytes.NewReader([]byte(p)), precision...)
+
+	line := make([][2]float64, 0, len(p)/4)
+	for {
+		point, err := d.Next()
+		if err == io.EOF {
+			return line, nil
+		}
+
+		if err != nil {
+			return line, err
+		}
+
+		line = append(line, point)
+	}
+}
+
+// PolylineDecoder decodes points one at a time from a reader,
+// so very long polylines don't need to be held in memory.
+type PolylineDecoder struct {
+	reader *bufio.Reader
+	factor float64
+	offset int
+	last   [2]int
+}
+
+// NewPolylineDecoder creates a decoder reading from r. The precision defaults to 5.
+func NewPolylineDecoder(r io.Reader, precision ...PolylinePrecision) *PolylineDecoder {
+	return &PolylineDecoder{
+		reader: bufio.NewReader(r),
+		factor: polylinePrecision(precision).factor(),
+	}
+}
 
-	line := make([][2]float64, 0)
-	tempLatLng := [2]int{0, 0}
+// Next returns the next [lat, lng] point. Returns io.EOF when there are no more points
+// or a *PolylineError if the polyline is malformed.
+func (d *PolylineDecoder) Next() ([2]float64, error) {
+	var values [2]int
+	for k := 0; k < 2; k++ {
+		v, err := d.readValue()
+		if err == io.EOF && k == 1 {
+			return [2]float64{}, &PolylineError{Offset: d.offset, message: "missing longitude"}
+		}
+
+		if err != nil {
+			return [2]float64{}, err
+		}
+
+		values[k] = d.last[k] + v
+	}
+
+	d.last = values
+	return [2]float64{float64(values[0]) / d.factor, float64(values[1]) / d.factor}, nil
+}
 
-	for index < len(p) {
-		var result int
-		var b int = 0x20
-		var shift uint
+func (d *PolylineDecoder) readValue() (int, error) {
+	var result int
+	var shift uint
 
-		for b >= 0x20 {
-			b = int(p[index]) - 63
-			index++
+	for {
+		c, err := d.reader.ReadByte()
+		if err == io.EOF && shift == 0 {
+			return 0, io.EOF
+		}
 
-			result |= (b & 0x1f) << shift
-			shift += 5
+		if err == io.EOF {
+			return 0, &PolylineError{Offset: d.offset, message: "truncated value"}
 		}
 
-		// sign dection
-		if result&1 != 0 {
-			result = ^(result >> 1)
-		} else {
-			result = result >> 1
+		if err != nil {
+			return 0, err
 		}
 
-		if count%2 == 0 {
-			result += tempLatLng[0]
-			tempLatLng[0] = result
-		} else {
-			result += tempLatLng[1]
-			tempLatLng[1] = result
+		if c < 63 || c > 126 {
+			return 0, &PolylineError{Offset: d.offset, message: "invalid character"}
+		}
+		d.offset++
 
-			line = append(line, [2]float64{float64(tempLatLng[0]) / factor, float64(tempLatLng[1]) / factor})
+		if shift > 30 {
+			return 0, &PolylineError{Offset: d.offset, message: "value overflow"}
 		}
 
-		count++
+		b := int(c) - 63
+		result |= (b & 0x1f) << shift
+		shift += 5
+
+		if b < 0x20 {
+			break
+		}
 	}
 
-	return line
+	// sign dection
+	if result&1 != 0 {
+		return ^(result >> 1), nil
+	}
+
+	return result >> 1, nil
 }
@@ -1,6 +1,10 @@
 package strava
 
 import (
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
 	"testing"
 )
 
@@ -19,3 +23,98 @@ func TestPolylineDecode(t *testing.T) {
 		}
 	}
 }
+
+func TestPolylineDecodeMalformed(t *testing.T) {
+	var encoded Polyline = "_p~iF~ps|U_ulLnnqC_mqNvxq"
+
+	// should not panic
+	latlng := encoded.Decode()
+	if len(latlng) != 2 {
+		t.Errorf("should decode points before the error, got %v", latlng)
+	}
+
+	_, err := encoded.DecodeE()
+	if e, ok := err.(*PolylineError); !ok || e.Offset != len(encoded) {
+		t.Errorf("should return polyline error, got %v", err)
+	}
+
+	_, err = Polyline("_p~iF~ps|U_ulL").DecodeE()
+	if _, ok := err.(*PolylineError); !ok {
+		t.Errorf("should return error for missing longitude, got %v", err)
+	}
+
+	_, err = Polyline("_p~iF ~ps|U").DecodeE()
+	if e, ok := err.(*PolylineError); !ok || e.Offset != 5 {
+		t.Errorf("should return error for invalid character, got %v", err)
+	}
+
+	latlng, err = Polyline("").DecodeE()
+	if err != nil || len(latlng) != 0 {
+		t.Errorf("empty polyline should decode to nothing, got %v %v", latlng, err)
+	}
+}
+
+func TestPolylineEncode(t *testing.T) {
+	points := [][2]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+	if p := EncodePolyline(points); p != "_p~iF~ps|U_ulLnnqC_mqNvxq`@" {
+		t.Errorf("polyline encoded incorrectly, got %v", p)
+	}
+
+	// round trip a strava polyline, see segment_get cassette
+	var encoded Polyline = "}g|eFnpqjVl@En@Md@HbAd@d@^h@Xx@VbARjBDh@OPQf@w@d@k@XKXDFPH\\EbGT`AV`@v@|@NTNb@?XOb@cAxAWLuE@eAFMBoAv@eBt@q@b@}@tAeAt@i@dAC`AFZj@dB?~@[h@MbAVn@b@b@\\d@Eh@Qb@_@d@eB|@c@h@WfBK|AMpA?VF\\\\t@f@t@h@j@|@b@hCb@b@XTd@Bl@GtA?jAL`ALp@Tr@RXd@Rx@Pn@^Zh@Tx@Zf@`@FTCzDy@f@Yx@m@n@Op@VJr@"
+	if p := EncodePolyline(encoded.Decode()); p != encoded {
+		t.Errorf("polyline round trip incorrect, got %v", p)
+	}
+
+	six := EncodePolyline(points, PolylinePrecisions.Six)
+	latlng, err := six.DecodeE(PolylinePrecisions.Six)
+	if err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	for i, v := range latlng {
+		if math.Abs(v[0]-points[i][0]) > 1e-9 || math.Abs(v[1]-points[i][1]) > 1e-9 {
+			t.Errorf("Polyline, precision 6 round trip error on element %d, expected %v, got %v", i, points[i], v)
+		}
+	}
+}
+
+func TestPolylineDecoder(t *testing.T) {
+	d := NewPolylineDecoder(strings.NewReader("_p~iF~ps|U_ulLnnqC_mqNvxq`@"))
+
+	count := 0
+	for {
+		_, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+		count++
+	}
+
+	if count != 3 {
+		t.Errorf("decoder returned incorrect number of points, got %d", count)
+	}
+}
+
+func TestPolylineMapJSON(t *testing.T) {
+	data, _ := json.Marshal(&PolylineMap{Id: "a123", Polyline: "_p~iF~ps|U"})
+	if string(data) != `{"id":"a123","polyline":"_p~iF~ps|U","summary_polyline":""}` {
+		t.Errorf("should keep the empty summary polyline of an activity, got %s", data)
+	}
+
+	data, _ = json.Marshal(PolylineMap{Id: "s123", Polyline: "_p~iF~ps|U"})
+	if string(data) != `{"id":"s123","polyline":"_p~iF~ps|U"}` {
+		t.Errorf("should omit the summary polyline of a segment, got %s", data)
+	}
+
+	var m PolylineMap
+	json.Unmarshal([]byte(`{"id":"a123","polyline":"","summary_polyline":"_p~iF~ps|U"}`), &m)
+	if m.SummaryPolyline != "_p~iF~ps|U" {
+		t.Errorf("should decode the summary polyline, got %v", m.SummaryPolyline)
+	}
+}
@@ -0,0 +1,250 @@
+package strava
+
+import (
+	"math"
+	"sort"
+)
+
+// A RouteCluster is a group of activities that follow the same route.
+type RouteCluster struct {
+	// Representative is the activity whose route is most similar to all the others.
+	// For large clusters it is chosen from an evenly spaced sample of the activities.
+	Representative *ActivitySummary
+	Activities     []*ActivitySummary
+
+	// Fastest has the lowest moving time.
+	Fastest            *ActivitySummary
+	AverageMovingTime  float64 // seconds
+	AverageElapsedTime float64 // seconds
+	AverageDistance    float64 // meters
+}
+
+type RouteMetric string
+
+var RouteMetrics = struct {
+	Frechet   RouteMetric // considers direction and order, so out and backs and loops in reverse differ
+	Hausdorff RouteMetric // only considers shape, direction does not matter
+}{"frechet", "hausdorff"}
+
+type RouteClusterOptions struct {
+	Metric RouteMetric
+
+	// Routes are the same if their distance, as measured by the Metric,
+	// is no more than this many meters.
+	Threshold float64
+}
+
+// largest number of routes compared to each other when choosing a cluster's representative
+const routeClusterSampleSize = 20
+
+// DefaultRouteClusterOptions are used by ClusterRoutes if no options are provided.
+var DefaultRouteClusterOptions = RouteClusterOptions{
+	Metric:    RouteMetrics.Frechet,
+	Threshold: 200,
+}
+
+// ClusterRoutes groups activities by the similarity of their Map.SummaryPolyline.
+// Activities without a summary polyline, eg. manual or trainer activities, are skipped.
+// Clusters are returned largest first.
+func ClusterRoutes(activities []*ActivitySummary, options ...RouteClusterOptions) []*RouteCluster {
+	opts := DefaultRouteClusterOptions
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	routes := make([]*clusterRoute, 0, len(activities))
+	for _, a := range activities {
+		line := a.Map.SummaryPolyline.Decode()
+		if len(line) < 2 {
+			continue
+		}
+
+		routes = append(routes, &clusterRoute{activity: a, line: line, bounds: NewBounds(line)})
+	}
+
+	// each route joins the first cluster whose leader it matches
+	groups := make([][]*clusterRoute, 0)
+	for _, r := range routes {
+		joined := false
+		for i, g := range groups {
+			if _, ok := routeDistance(g[0], r, opts); ok {
+				groups[i] = append(g, r)
+				joined = true
+				break
+			}
+		}
+
+		if !joined {
+			groups = append(groups, []*clusterRoute{r})
+		}
+	}
+
+	clusters := make([]*RouteCluster, 0, len(groups))
+	for _, g := range groups {
+		clusters = append(clusters, newRouteCluster(g, opts))
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return len(clusters[i].Activities) > len(clusters[j].Activities)
+	})
+
+	return clusters
+}
+
+// DiscreteFrechetDistance returns the discrete Fréchet distance in meters between
+// two lines of [lat, lng] points. It is the shortest leash that would let a person walk
+// one line while their dog walks the other, neither going backwards.
+func DiscreteFrechetDistance(a, b [][2]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.Inf(1)
+	}
+
+	prev := make([]float64, len(b))
+	curr := make([]float64, len(b))
+
+	for i := range a {
+		for j := range b {
+			d := Location(a[i]).DistanceTo(b[j])
+
+			switch {
+			case i == 0 && j == 0:
+				curr[j] = d
+			case i == 0:
+				curr[j] = math.Max(curr[j-1], d)
+			case j == 0:
+				curr[j] = math.Max(prev[j], d)
+			default:
+				curr[j] = math.Max(math.Min(prev[j], math.Min(prev[j-1], curr[j-1])), d)
+			}
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)-1]
+}
+
+// HausdorffDistance returns the Hausdorff distance in meters between two lines
+// of [lat, lng] points, the furthest any point on one is from the closest point on the other.
+func HausdorffDistance(a, b [][2]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return math.Inf(1)
+	}
+
+	return math.Max(directedHausdorff(a, b), directedHausdorff(b, a))
+}
+
+func directedHausdorff(a, b [][2]float64) float64 {
+	max := 0.0
+	for _, p := range a {
+		min := math.Inf(1)
+		for _, q := range b {
+			if d := Location(p).DistanceTo(q); d < min {
+				min = d
+
+				// can't increase the maximum
+				if min <= max {
+					break
+				}
+			}
+		}
+
+		max = math.Max(max, min)
+	}
+
+	return max
+}
+
+/*********************************************************/
+
+type clusterRoute struct {
+	activity *ActivitySummary
+	line     [][2]float64
+	bounds   Bounds
+}
+
+// routeDistance returns the distance between the routes and whether it is within the threshold.
+// Routes that can't be within the threshold are rejected using their bounds and end points
+// before the expensive metric is computed.
+func routeDistance(a, b *clusterRoute, opts RouteClusterOptions) (float64, bool) {
+	// if every point is within the threshold of the other line,
+	// the bounds can't differ by more than the threshold
+	corners := [][2]Location{
+		{{a.bounds.South, a.bounds.West}, {b.bounds.South, a.bounds.West}},
+		{{a.bounds.North, a.bounds.West}, {b.bounds.North, a.bounds.West}},
+		{{a.bounds.South, a.bounds.West}, {a.bounds.South, b.bounds.West}},
+		{{a.bounds.South, a.bounds.East}, {a.bounds.South, b.bounds.East}},
+	}
+
+	for _, c := range corners {
+		if c[0].DistanceTo(c[1]) > opts.Threshold {
+			return math.Inf(1), false
+		}
+	}
+
+	var d float64
+	if opts.Metric == RouteMetrics.Hausdorff {
+		d = HausdorffDistance(a.line, b.line)
+	} else {
+		if Location(a.line[0]).DistanceTo(b.line[0]) > opts.Threshold ||
+			Location(a.line[len(a.line)-1]).DistanceTo(b.line[len(b.line)-1]) > opts.Threshold {
+			return math.Inf(1), false
+		}
+
+		d = DiscreteFrechetDistance(a.line, b.line)
+	}
+
+	return d, d <= opts.Threshold
+}
+
+func newRouteCluster(routes []*clusterRoute, opts RouteClusterOptions) *RouteCluster {
+	cluster := &RouteCluster{
+		Activities: make([]*ActivitySummary, 0, len(routes)),
+	}
+
+	// the representative is the medoid, closest in total to all the others,
+	// of a sample as each comparison is quadratic in the number of points
+	sample := routes
+	if len(routes) > routeClusterSampleSize {
+		sample = make([]*clusterRoute, routeClusterSampleSize)
+		for i := range sample {
+			sample[i] = routes[i*len(routes)/routeClusterSampleSize]
+		}
+	}
+
+	best := math.Inf(1)
+	for _, r := range sample {
+		total := 0.0
+		for _, other := range sample {
+			if r != other {
+				d, _ := routeDistance(r, other, RouteClusterOptions{opts.Metric, math.Inf(1)})
+				total += d
+			}
+		}
+
+		if total < best {
+			best = total
+			cluster.Representative = r.activity
+		}
+	}
+
+	for _, r := range routes {
+		a := r.activity
+		cluster.Activities = append(cluster.Activities, a)
+
+		cluster.AverageMovingTime += float64(a.MovingTime)
+		cluster.AverageElapsedTime += float64(a.ElapsedTime)
+		cluster.AverageDistance += a.Distance
+
+		if a.MovingTime > 0 && (cluster.Fastest == nil || a.MovingTime < cluster.Fastest.MovingTime) {
+			cluster.Fastest = a
+		}
+	}
+
+	count := float64(len(routes))
+	cluster.AverageMovingTime /= count
+	cluster.AverageElapsedTime /= count
+	cluster.AverageDistance /= count
+
+	return cluster
+}
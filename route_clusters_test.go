@@ -0,0 +1,153 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+// newTestRoute returns a line north from the start with a small east offset,
+// about 111 meters between points.
+func newTestRoute(lat, lng, offset float64, count int) [][2]float64 {
+	line := make([][2]float64, count)
+	for i := range line {
+		line[i] = [2]float64{lat + float64(i)*0.001, lng + offset}
+	}
+
+	return line
+}
+
+func reverseTestRoute(line [][2]float64) [][2]float64 {
+	reversed := make([][2]float64, len(line))
+	for i, p := range line {
+		reversed[len(line)-1-i] = p
+	}
+
+	return reversed
+}
+
+func newTestRouteActivity(id int64, line [][2]float64, movingTime int) *ActivitySummary {
+	a := &ActivitySummary{}
+	a.Id = id
+	a.Map.SummaryPolyline = EncodePolyline(line)
+	a.MovingTime = movingTime
+	a.ElapsedTime = movingTime + 60
+	a.Distance = 1000
+
+	return a
+}
+
+func TestRouteDistances(t *testing.T) {
+	a := newTestRoute(37.8, -122.4, 0, 10)
+	b := newTestRoute(37.8, -122.4, 0.001, 10) // about 88 meters east
+
+	if d := DiscreteFrechetDistance(a, b); math.Abs(d-88) > 1 {
+		t.Errorf("frechet distance incorrect, got %v", d)
+	}
+
+	if d := HausdorffDistance(a, b); math.Abs(d-88) > 1 {
+		t.Errorf("hausdorff distance incorrect, got %v", d)
+	}
+
+	// same shape, opposite direction
+	r := reverseTestRoute(a)
+	if d := HausdorffDistance(a, r); d != 0 {
+		t.Errorf("hausdorff distance should ignore direction, got %v", d)
+	}
+
+	if d := DiscreteFrechetDistance(a, r); d < 900 {
+		t.Errorf("frechet distance should consider direction, got %v", d)
+	}
+
+	if d := DiscreteFrechetDistance(a, a); d != 0 {
+		t.Errorf("distance to self should be 0, got %v", d)
+	}
+
+	if d := HausdorffDistance(a, nil); !math.IsInf(d, 1) {
+		t.Errorf("distance to nothing should be infinite, got %v", d)
+	}
+}
+
+func TestClusterRoutes(t *testing.T) {
+	commute := newTestRoute(37.8, -122.4, 0, 20)
+	loop := newTestRoute(37.5, -122.2, 0, 20)
+
+	activities := []*ActivitySummary{
+		newTestRouteActivity(1, commute, 600),
+		newTestRouteActivity(2, loop, 1000),
+		newTestRouteActivity(3, newTestRoute(37.8, -122.4, 0.0005, 20), 500),
+		newTestRouteActivity(4, newTestRoute(37.8, -122.4, -0.0005, 20), 700),
+		newTestRouteActivity(5, reverseTestRoute(commute), 650),
+		{}, // no polyline, eg. on the trainer
+	}
+
+	clusters := ClusterRoutes(activities)
+	if len(clusters) != 3 {
+		t.Fatalf("incorrect number of clusters, got %d", len(clusters))
+	}
+
+	c := clusters[0]
+	if len(c.Activities) != 3 {
+		t.Fatalf("incorrect number of activities, got %d", len(c.Activities))
+	}
+
+	if c.Representative.Id != 1 {
+		t.Errorf("representative should be the middle route, got %d", c.Representative.Id)
+	}
+
+	if c.Fastest.Id != 3 {
+		t.Errorf("fastest incorrect, got %d", c.Fastest.Id)
+	}
+
+	if c.AverageMovingTime != 600 {
+		t.Errorf("average moving time incorrect, got %v", c.AverageMovingTime)
+	}
+
+	if c.AverageElapsedTime != 660 {
+		t.Errorf("average elapsed time incorrect, got %v", c.AverageElapsedTime)
+	}
+
+	if c.AverageDistance != 1000 {
+		t.Errorf("average distance incorrect, got %v", c.AverageDistance)
+	}
+
+	// direction doesn't matter with hausdorff
+	clusters = ClusterRoutes(activities, RouteClusterOptions{RouteMetrics.Hausdorff, 200})
+	if len(clusters) != 2 {
+		t.Fatalf("incorrect number of clusters, got %d", len(clusters))
+	}
+
+	if len(clusters[0].Activities) != 4 {
+		t.Errorf("incorrect number of activities, got %d", len(clusters[0].Activities))
+	}
+
+	if clusters[1].Representative.Id != 2 {
+		t.Errorf("representative incorrect, got %d", clusters[1].Representative.Id)
+	}
+
+	// too strict to group anything
+	clusters = ClusterRoutes(activities, RouteClusterOptions{RouteMetrics.Frechet, 10})
+	if len(clusters) != 5 {
+		t.Errorf("incorrect number of clusters, got %d", len(clusters))
+	}
+
+	if clusters := ClusterRoutes(nil); len(clusters) != 0 {
+		t.Errorf("should have no clusters, got %d", len(clusters))
+	}
+}
+
+func TestClusterRoutesLargeCluster(t *testing.T) {
+	// 100 commutes about a meter apart, west to east
+	activities := make([]*ActivitySummary, 100)
+	for i := range activities {
+		activities[i] = newTestRouteActivity(int64(i), newTestRoute(37.8, -122.4, float64(i-50)*0.00001, 50), 600)
+	}
+
+	clusters := ClusterRoutes(activities)
+	if len(clusters) != 1 || len(clusters[0].Activities) != 100 {
+		t.Fatalf("should be one cluster of every activity, got %d", len(clusters))
+	}
+
+	if id := clusters[0].Representative.Id; id < 40 || id > 60 {
+		t.Errorf("representative should be near the middle, got %d", id)
+	}
+}
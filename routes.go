@@ -0,0 +1,195 @@
+package strava
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+type RouteDetailed struct {
+	RouteSummary
+	Segments []*SegmentSummary `json:"segments"`
+}
+
+type RouteSummary struct {
+	Id                  int64          `json:"id"`
+	Name                string         `json:"name"`
+	Description         string         `json:"description"`
+	Athlete             AthleteSummary `json:"athlete"`
+	Distance            float64        `json:"distance"`
+	ElevationGain       float64        `json:"elevation_gain"`
+	EstimatedMovingTime int            `json:"estimated_moving_time"`
+	Map                 PolylineMap    `json:"map"`
+	Type                RouteType      `json:"type"`
+	SubType             RouteSubType   `json:"sub_type"`
+	Private             bool           `json:"private"`
+	Starred             bool           `json:"starred"`
+	Timestamp           int64          `json:"timestamp"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+type RouteType int
+
+var RouteTypes = struct {
+	Ride RouteType
+	Run  RouteType
+}{1, 2}
+
+type RouteSubType int
+
+var RouteSubTypes = struct {
+	Road         RouteSubType
+	MountainBike RouteSubType
+	Cyclocross   RouteSubType
+	Trail        RouteSubType
+	Mixed        RouteSubType
+}{1, 2, 3, 4, 5}
+
+type RoutesService struct {
+	client *Client
+}
+
+func NewRoutesService(client *Client) *RoutesService {
+	return &RoutesService{client}
+}
+
+/*********************************************************/
+
+type RoutesGetCall struct {
+	service *RoutesService
+	id      int64
+}
+
+func (s *RoutesService) Get(routeId int64) *RoutesGetCall {
+	return &RoutesGetCall{
+		service: s,
+		id:      routeId,
+	}
+}
+
+func (c *RoutesGetCall) Do() (*RouteDetailed, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/routes/%d", c.id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var route RouteDetailed
+	err = json.Unmarshal(data, &route)
+	if err != nil {
+		return nil, err
+	}
+
+	return &route, nil
+}
+
+/*********************************************************/
+
+type RoutesListByAthleteCall struct {
+	service *RoutesService
+	id      int64
+	ops     map[string]interface{}
+}
+
+func (s *RoutesService) ListByAthlete(athleteId int64) *RoutesListByAthleteCall {
+	return &RoutesListByAthleteCall{
+		service: s,
+		id:      athleteId,
+		ops:     make(map[string]interface{}),
+	}
+}
+
+func (c *RoutesListByAthleteCall) Page(page int) *RoutesListByAthleteCall {
+	c.ops["page"] = page
+	return c
+}
+
+func (c *RoutesListByAthleteCall) PerPage(perPage int) *RoutesListByAthleteCall {
+	c.ops["per_page"] = perPage
+	return c
+}
+
+func (c *RoutesListByAthleteCall) Do() ([]*RouteSummary, error) {
+	data, err := c.service.client.run("GET", fmt.Sprintf("/athletes/%d/routes", c.id), c.ops)
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]*RouteSummary, 0)
+	err = json.Unmarshal(data, &routes)
+	if err != nil {
+		return nil, err
+	}
+
+	return routes, nil
+}
+
+/*********************************************************/
+
+type RoutesExportCall struct {
+	service *RoutesService
+	id      int64
+	format  string
+}
+
+// ExportGPX downloads the route as a GPX file.
+func (s *RoutesService) ExportGPX(routeId int64) *RoutesExportCall {
+	return &RoutesExportCall{
+		service: s,
+		id:      routeId,
+		format:  "gpx",
+	}
+}
+
+// ExportTCX downloads the route as a TCX file.
+func (s *RoutesService) ExportTCX(routeId int64) *RoutesExportCall {
+	return &RoutesExportCall{
+		service: s,
+		id:      routeId,
+		format:  "tcx",
+	}
+}
+
+// Do returns the file contents, which must be closed by the caller.
+func (c *RoutesExportCall) Do() (io.ReadCloser, error) {
+	return c.service.client.runReader("GET", fmt.Sprintf("/routes/%d/export_%s", c.id, c.format), nil)
+}
+
+/*********************************************************/
+
+func (t RouteType) Id() int {
+	return int(t)
+}
+
+func (t RouteType) String() string {
+	switch t.Id() {
+	case 1:
+		return "Ride"
+	case 2:
+		return "Run"
+	}
+
+	return "Unknown"
+}
+
+func (t RouteSubType) Id() int {
+	return int(t)
+}
+
+func (t RouteSubType) String() string {
+	switch t.Id() {
+	case 1:
+		return "Road"
+	case 2:
+		return "Mountain Bike"
+	case 3:
+		return "Cyclocross"
+	case 4:
+		return "Trail"
+	case 5:
+		return "Mixed"
+	}
+
+	return "Unknown"
+}
@@ -0,0 +1,169 @@
+package strava
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+const testRouteJSON = `{"athlete":{"id":227615,"resource_state":2,"firstname":"John","lastname":"Applestrava"},"description":"Hawk Hill and back","distance":16523.2,"elevation_gain":312.5,"id":1234,"map":{"id":"r1234","polyline":"}g|eFnpqjVl@En@Md@HbAd@d@^h@Xx@VbARjBDh@OPQf@w@","summary_polyline":"}g|eFnpqjV~@SbAd@"},"name":"Hawk Hill Loop","private":false,"starred":true,"timestamp":1388617200,"type":1,"sub_type":1,"estimated_moving_time":2580,"segments":[{"id":229781,"name":"Hawk Hill","activity_type":"Ride","distance":2684.82,"climb_category":1}]}`
+
+func TestRoutesGet(t *testing.T) {
+	client := NewStubResponseClient(testRouteJSON)
+	route, err := NewRoutesService(client).Get(1234).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if route.Id != 1234 || route.Name != "Hawk Hill Loop" || route.Athlete.Id != 227615 {
+		t.Errorf("route not parsed, got %v", route)
+	}
+
+	if route.Type != RouteTypes.Ride || route.SubType != RouteSubTypes.Road {
+		t.Errorf("route type incorrect, got %v %v", route.Type, route.SubType)
+	}
+
+	if route.Distance != 16523.2 || route.ElevationGain != 312.5 || route.EstimatedMovingTime != 2580 {
+		t.Errorf("route values incorrect, got %v", route)
+	}
+
+	if route.Map.Polyline == "" || route.Map.SummaryPolyline == "" || !route.Starred {
+		t.Errorf("route values incorrect, got %v", route)
+	}
+
+	if len(route.Segments) != 1 || route.Segments[0].Id != 229781 || route.Segments[0].ClimbCategory != ClimbCategories.Category4 {
+		t.Errorf("route segments incorrect, got %v", route.Segments)
+	}
+
+	// from here on out just check the request parameters
+	s := NewRoutesService(newStoreRequestClient())
+
+	// path
+	s.Get(123).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/routes/123" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+}
+
+func TestRoutesListByAthlete(t *testing.T) {
+	client := NewStubResponseClient("[" + testRouteJSON + "]")
+	routes, err := NewRoutesService(client).ListByAthlete(227615).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(routes) != 1 || routes[0].Id != 1234 {
+		t.Fatalf("routes not parsed, got %v", routes)
+	}
+
+	// from here on out just check the request parameters
+	s := NewRoutesService(newStoreRequestClient())
+
+	// path
+	s.ListByAthlete(123).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/athletes/123/routes" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	if transport.request.URL.RawQuery != "" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+
+	// parameters
+	s.ListByAthlete(123).Page(2).PerPage(3).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.RawQuery != "page=2&per_page=3" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+}
+
+func TestRoutesExport(t *testing.T) {
+	gpx := `<?xml version="1.0" encoding="UTF-8"?><gpx version="1.1"></gpx>`
+
+	reader, err := NewRoutesService(NewStubResponseClient(gpx)).ExportGPX(1234).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	data, _ := ioutil.ReadAll(reader)
+	reader.Close()
+
+	if string(data) != gpx {
+		t.Errorf("export incorrect, got %s", data)
+	}
+
+	// from here on out just check the request parameters
+	s := NewRoutesService(newStoreRequestClient())
+
+	s.ExportGPX(123).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/routes/123/export_gpx" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	s.ExportTCX(123).Do()
+
+	transport = s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/routes/123/export_tcx" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+
+	// errors
+	_, err = NewRoutesService(NewStubResponseClient(`{"message":"Record Not Found"}`, http.StatusNotFound)).ExportTCX(123).Do()
+	if err == nil {
+		t.Error("should return a not found error")
+	}
+}
+
+func TestRouteStreams(t *testing.T) {
+	client := NewStubResponseClient(`[{"type":"latlng","data":[[37.83,-122.48],[37.84,-122.49]]},{"type":"distance","data":[0.0,1410.2]},{"type":"altitude","data":[10.5,160.1]}]`)
+	streams, err := NewRouteStreamsService(client).Get(1234).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if streams.Location == nil || len(streams.Location.Data) != 2 || streams.Location.Data[1] != [2]float64{37.84, -122.49} {
+		t.Errorf("location stream incorrect, got %v", streams.Location)
+	}
+
+	if streams.Distance == nil || streams.Distance.Data[1] != 1410.2 {
+		t.Errorf("distance stream incorrect, got %v", streams.Distance)
+	}
+
+	if streams.Elevation == nil || streams.Elevation.Data[0] != 10.5 {
+		t.Errorf("elevation stream incorrect, got %v", streams.Elevation)
+	}
+
+	// path
+	s := NewRouteStreamsService(newStoreRequestClient())
+	s.Get(123).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/routes/123/streams" {
+		t.Errorf("request path incorrect, got %v", transport.request.URL.Path)
+	}
+}
+
+func TestRoutesBadJSON(t *testing.T) {
+	var err error
+	s := NewRoutesService(NewStubResponseClient("bad json"))
+
+	_, err = s.Get(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+
+	_, err = s.ListByAthlete(123).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+}
@@ -0,0 +1,40 @@
+package strava
+
+import (
+	"encoding/json"
+)
+
+// SchemaVersion is the version of the JSON the models marshal to. It changes when
+// a field is renamed or removed, so objects stored by MarshalVersioned can be migrated.
+// Models marshal to the same field names Strava sends.
+const SchemaVersion = 1
+
+type versionedObject struct {
+	SchemaVersion int             `json:"schema_version"`
+	Object        json.RawMessage `json:"object"`
+}
+
+// MarshalVersioned marshals a model, eg. an ActivityDetailed, along with the SchemaVersion for storage.
+func MarshalVersioned(v interface{}) ([]byte, error) {
+	object, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&versionedObject{SchemaVersion, object})
+}
+
+// UnmarshalVersioned unmarshals an object stored with MarshalVersioned and returns its version.
+// Objects from a newer version return a *SchemaVersionError.
+func UnmarshalVersioned(data []byte, v interface{}) (int, error) {
+	var object versionedObject
+	if err := json.Unmarshal(data, &object); err != nil {
+		return 0, err
+	}
+
+	if object.SchemaVersion > SchemaVersion {
+		return object.SchemaVersion, &SchemaVersionError{object.SchemaVersion}
+	}
+
+	return object.SchemaVersion, json.Unmarshal(object.Object, v)
+}
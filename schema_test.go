@@ -0,0 +1,174 @@
+package strava
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+// model types of each cassette body
+var cassetteModels = map[string]func() interface{}{
+	"activity_comments_list":                  func() interface{} { return &[]*CommentSummary{} },
+	"activity_comments_post":                  func() interface{} { return &CommentDetailed{} },
+	"activity_get":                            func() interface{} { return &ActivityDetailed{} },
+	"activity_get_ride_all_efforts":           func() interface{} { return &ActivityDetailed{} },
+	"activity_get_run":                        func() interface{} { return &ActivityDetailed{} },
+	"activity_kudos_list":                     func() interface{} { return &[]*AthleteSummary{} },
+	"activity_list_laps":                      func() interface{} { return &[]*LapEffortSummary{} },
+	"activity_list_photos":                    func() interface{} { return &[]*PhotoSummary{} },
+	"activity_list_zones":                     func() interface{} { return &[]*ZonesSummary{} },
+	"activity_post":                           func() interface{} { return &ActivityDetailed{} },
+	"activity_put":                            func() interface{} { return &ActivityDetailed{} },
+	"activity_stream":                         func() interface{} { return &StreamSet{} },
+	"athlete_get":                             func() interface{} { return &AthleteSummary{} },
+	"athlete_list_activies":                   func() interface{} { return &[]*ActivitySummary{} },
+	"athlete_list_both_following":             func() interface{} { return &[]*AthleteSummary{} },
+	"athlete_list_followers":                  func() interface{} { return &[]*AthleteSummary{} },
+	"athlete_list_friends":                    func() interface{} { return &[]*AthleteSummary{} },
+	"athlete_list_koms":                       func() interface{} { return &[]*SegmentEffortSummary{} },
+	"athlete_list_starred_segments":           func() interface{} { return &[]*PersonalSegmentSummary{} },
+	"athlete_stats":                           func() interface{} { return &AthleteStats{} },
+	"club_get":                                func() interface{} { return &ClubDetailed{} },
+	"club_list_activities":                    func() interface{} { return &[]*ActivitySummary{} },
+	"club_list_members":                       func() interface{} { return &[]*AthleteSummary{} },
+	"current_athlete_get":                     func() interface{} { return &AthleteDetailed{} },
+	"current_athlete_list_activities":         func() interface{} { return &[]*ActivitySummary{} },
+	"current_athlete_list_clubs":              func() interface{} { return &[]*ClubSummary{} },
+	"current_athlete_list_followers":          func() interface{} { return &[]*AthleteSummary{} },
+	"current_athlete_list_friends":            func() interface{} { return &[]*AthleteSummary{} },
+	"current_athlete_list_friends_activities": func() interface{} { return &[]*ActivitySummary{} },
+	"current_athlete_list_starred_segments":   func() interface{} { return &[]*PersonalSegmentSummary{} },
+	"current_athlete_put":                     func() interface{} { return &AthleteDetailed{} },
+	"gear_get_bike":                           func() interface{} { return &GearDetailed{} },
+	"gear_get_shoe":                           func() interface{} { return &GearDetailed{} },
+	"segment_effort_get":                      func() interface{} { return &SegmentEffortDetailed{} },
+	"segment_explore":                         func() interface{} { return &segmentExplorer{} },
+	"segment_get":                             func() interface{} { return &SegmentDetailed{} },
+	"segment_get_leaderboard":                 func() interface{} { return &SegmentLeaderboard{} },
+	"segment_list_efforts":                    func() interface{} { return &[]*SegmentEffortSummary{} },
+	"upload_create":                           func() interface{} { return &UploadDetailed{} },
+	"upload_create_gz":                        func() interface{} { return &UploadDetailed{} },
+	"upload_create_unauthorized":              func() interface{} { return &Error{} },
+	"upload_get":                              func() interface{} { return &UploadDetailed{} },
+}
+
+func TestCassetteRoundTrip(t *testing.T) {
+	for cassette, model := range cassetteModels {
+		body, err := ioutil.ReadFile(cassetteDirectory + "/" + cassette + ".body")
+		if err != nil {
+			t.Fatalf("%s: %v", cassette, err)
+		}
+
+		decoded := model()
+		if err := json.Unmarshal(body, decoded); err != nil {
+			t.Errorf("%s: unmarshal error: %v", cassette, err)
+			continue
+		}
+
+		data, err := json.Marshal(decoded)
+		if err != nil {
+			t.Errorf("%s: marshal error: %v", cassette, err)
+			continue
+		}
+
+		again := model()
+		json.Unmarshal(data, again)
+		if !reflect.DeepEqual(decoded, again) {
+			t.Errorf("%s: changed after round trip", cassette)
+		}
+
+		// the fields that are set must have Strava's names and values
+		var original, marshaled interface{}
+		json.Unmarshal(body, &original)
+		json.Unmarshal(data, &marshaled)
+
+		if _, ok := decoded.(*StreamSet); ok {
+			original, marshaled = streamsByType(original), streamsByType(marshaled)
+		}
+
+		for _, diff := range jsonDifferences(cassette, original, marshaled) {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestMarshalVersioned(t *testing.T) {
+	segment := &SegmentDetailed{}
+	segment.Id = 229781
+	segment.Map.Polyline = "}g|eFnm@n@Op@VJr@"
+
+	data, err := MarshalVersioned(segment)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	var decoded SegmentDetailed
+	version, err := UnmarshalVersioned(data, &decoded)
+	if err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if version != SchemaVersion || !reflect.DeepEqual(segment, &decoded) {
+		t.Errorf("segment not preserved, got %v", decoded)
+	}
+
+	// errors
+	_, err = UnmarshalVersioned([]byte(fmt.Sprintf(`{"schema_version":%d,"object":{}}`, SchemaVersion+1)), &decoded)
+	if e, ok := err.(*SchemaVersionError); !ok || e.Version != SchemaVersion+1 {
+		t.Errorf("should return a schema version error, got %v", err)
+	}
+
+	if _, err := UnmarshalVersioned([]byte("bad json"), &decoded); err == nil {
+		t.Error("should return a bad json error")
+	}
+}
+
+// jsonDifferences compares marshaled json with the original, ignoring
+// fields that are not modeled or were null or missing in the original.
+func jsonDifferences(path string, original, marshaled interface{}) []string {
+	if original == nil {
+		return nil
+	}
+
+	diffs := make([]string, 0)
+	switch m := marshaled.(type) {
+	case map[string]interface{}:
+		o, ok := original.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected %v, got an object", path, original)}
+		}
+
+		for k, v := range m {
+			diffs = append(diffs, jsonDifferences(path+"."+k, o[k], v)...)
+		}
+
+	case []interface{}:
+		o, ok := original.([]interface{})
+		if !ok || len(o) != len(m) {
+			return []string{fmt.Sprintf("%s: expected %v, got %v", path, original, marshaled)}
+		}
+
+		for i := range m {
+			diffs = append(diffs, jsonDifferences(fmt.Sprintf("%s[%d]", path, i), o[i], m[i])...)
+		}
+
+	default:
+		if !reflect.DeepEqual(original, marshaled) {
+			diffs = append(diffs, fmt.Sprintf("%s: expected %v, got %v", path, original, marshaled))
+		}
+	}
+
+	return diffs
+}
+
+// streamsByType keys a list of streams by type, as their order is not kept.
+func streamsByType(streams interface{}) map[string]interface{} {
+	byType := make(map[string]interface{})
+	for _, s := range streams.([]interface{}) {
+		byType[s.(map[string]interface{})["type"].(string)] = s
+	}
+
+	return byType
+}
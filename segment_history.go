@@ -0,0 +1,207 @@
+package strava
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// the most efforts ListEfforts will return per page
+const segmentEffortsPerPage = 200
+
+// SegmentHistory is all of an athlete's efforts on a segment, see SegmentsService.History.
+type SegmentHistory struct {
+	SegmentId int64
+	AthleteId int64
+
+	// Efforts are in the order they happened, oldest first.
+	Efforts []*SegmentEffortSummary
+
+	// Progression are the efforts that were a personal record at the time, oldest first.
+	// The last is the current personal record.
+	Progression []*SegmentEffortSummary
+
+	// Consistency of the elapsed times, in seconds.
+	MedianElapsedTime  float64
+	InterquartileRange float64
+	StandardDeviation  float64
+}
+
+// A SegmentHistoryRank is where a personal record would place on a leaderboard.
+type SegmentHistoryRank struct {
+	Effort *SegmentEffortSummary
+
+	// Rank is 0 if the effort would be beyond the entries of the leaderboard.
+	Rank int
+
+	// Delta is the change from the previous personal record's rank, negative is an improvement.
+	// 0 for the first, or if either rank is unknown.
+	Delta int
+}
+
+type SegmentsHistoryCall struct {
+	service   *SegmentsService
+	segmentId int64
+	athleteId int64
+	ops       map[string]interface{}
+}
+
+// History gathers all the athlete's efforts on the segment, requesting pages from ListEfforts
+// until they run out, and computes the personal record progression and consistency.
+func (s *SegmentsService) History(segmentId, athleteId int64) *SegmentsHistoryCall {
+	return &SegmentsHistoryCall{
+		service:   s,
+		segmentId: segmentId,
+		athleteId: athleteId,
+		ops:       make(map[string]interface{}),
+	}
+}
+
+func (c *SegmentsHistoryCall) DateRange(startDateLocal, endDateLocal time.Time) *SegmentsHistoryCall {
+	c.ops["start_date_local"] = startDateLocal.UTC().Format(timeFormat)
+	c.ops["end_date_local"] = endDateLocal.UTC().Format(timeFormat)
+	return c
+}
+
+func (c *SegmentsHistoryCall) Do() (*SegmentHistory, error) {
+	efforts := make([]*SegmentEffortSummary, 0)
+	for page := 1; ; page++ {
+		call := c.service.ListEfforts(c.segmentId).AthleteId(c.athleteId).Page(page).PerPage(segmentEffortsPerPage)
+		for k, v := range c.ops {
+			call.ops[k] = v
+		}
+
+		found, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+
+		efforts = append(efforts, found...)
+		if len(found) < segmentEffortsPerPage {
+			break
+		}
+	}
+
+	return NewSegmentHistory(c.segmentId, c.athleteId, efforts), nil
+}
+
+// NewSegmentHistory computes the history from efforts already loaded,
+// eg. from a cache. The efforts do not need to be in order.
+func NewSegmentHistory(segmentId, athleteId int64, efforts []*SegmentEffortSummary) *SegmentHistory {
+	h := &SegmentHistory{
+		SegmentId:   segmentId,
+		AthleteId:   athleteId,
+		Efforts:     make([]*SegmentEffortSummary, len(efforts)),
+		Progression: make([]*SegmentEffortSummary, 0),
+	}
+
+	copy(h.Efforts, efforts)
+	sort.SliceStable(h.Efforts, func(i, j int) bool {
+		return h.Efforts[i].StartDate.Before(h.Efforts[j].StartDate)
+	})
+
+	for _, e := range h.Efforts {
+		if best := h.Best(); best == nil || e.ElapsedTime < best.ElapsedTime {
+			h.Progression = append(h.Progression, e)
+		}
+	}
+
+	if len(h.Efforts) == 0 {
+		return h
+	}
+
+	times := make([]float64, len(h.Efforts))
+	mean := 0.0
+	for i, e := range h.Efforts {
+		times[i] = float64(e.ElapsedTime)
+		mean += times[i]
+	}
+	mean /= float64(len(times))
+	sort.Float64s(times)
+
+	variance := 0.0
+	for _, t := range times {
+		variance += (t - mean) * (t - mean)
+	}
+
+	h.MedianElapsedTime = quantile(times, 0.5)
+	h.InterquartileRange = quantile(times, 0.75) - quantile(times, 0.25)
+	h.StandardDeviation = math.Sqrt(variance / float64(len(times)))
+
+	return h
+}
+
+// Best returns the personal record, or nil if there are no efforts.
+func (h *SegmentHistory) Best() *SegmentEffortSummary {
+	if len(h.Progression) == 0 {
+		return nil
+	}
+
+	return h.Progression[len(h.Progression)-1]
+}
+
+// Ranks returns where each personal record of the progression would place on the leaderboard,
+// eg. the result of SegmentsService.GetLeaderboard. The athlete's own entry is ignored.
+func (h *SegmentHistory) Ranks(leaderboard *SegmentLeaderboard) []*SegmentHistoryRank {
+	ownRank, lastRank, lastTime := 0, 0, 0
+	for _, entry := range leaderboard.Entries {
+		if entry.AthleteId == h.AthleteId {
+			ownRank = entry.Rank
+		}
+
+		if entry.Rank > lastRank {
+			lastRank, lastTime = entry.Rank, entry.ElapsedTime
+		}
+	}
+
+	ranks := make([]*SegmentHistoryRank, 0, len(h.Progression))
+	for _, e := range h.Progression {
+		r := &SegmentHistoryRank{Effort: e, Rank: 1}
+
+		if lastRank < leaderboard.EntryCount && lastTime < e.ElapsedTime {
+			// slower than all the loaded entries
+			r.Rank = 0
+		} else {
+			// placed after the slowest athlete that was faster
+			for _, entry := range leaderboard.Entries {
+				if entry.AthleteId == h.AthleteId || entry.ElapsedTime >= e.ElapsedTime {
+					continue
+				}
+
+				rank := entry.Rank + 1
+				if ownRank != 0 && ownRank < entry.Rank {
+					rank--
+				}
+
+				if rank > r.Rank {
+					r.Rank = rank
+				}
+			}
+		}
+
+		if len(ranks) != 0 {
+			if previous := ranks[len(ranks)-1].Rank; previous != 0 && r.Rank != 0 {
+				r.Delta = r.Rank - previous
+			}
+		}
+
+		ranks = append(ranks, r)
+	}
+
+	return ranks
+}
+
+// quantile returns the q quantile of the sorted values, interpolating between them.
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	position := q * float64(len(sorted)-1)
+	i := int(position)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[i] + (sorted[i+1]-sorted[i])*(position-float64(i))
+}
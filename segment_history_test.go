@@ -0,0 +1,171 @@
+package strava
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestHistoryEffort(day, elapsedTime int) *SegmentEffortSummary {
+	e := &SegmentEffortSummary{}
+	e.Id = int64(day)
+	e.ElapsedTime = elapsedTime
+	e.StartDate = time.Date(2014, 1, day, 8, 0, 0, 0, time.UTC)
+
+	return e
+}
+
+// pagedEffortsTransport returns count efforts split into pages of per_page
+type pagedEffortsTransport struct {
+	http.Transport
+	count    int
+	requests []*http.Request
+}
+
+func (t *pagedEffortsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+
+	var page, perPage int
+	json.Unmarshal([]byte(req.URL.Query().Get("page")), &page)
+	json.Unmarshal([]byte(req.URL.Query().Get("per_page")), &perPage)
+
+	efforts := make([]*SegmentEffortSummary, 0)
+	for i := (page - 1) * perPage; i < page*perPage && i < t.count; i++ {
+		efforts = append(efforts, newTestHistoryEffort(i%28+1, 300+i))
+	}
+
+	data, _ := json.Marshal(efforts)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader(string(data))),
+	}, nil
+}
+
+func TestSegmentsHistory(t *testing.T) {
+	transport := &pagedEffortsTransport{count: 203}
+	client := NewClient("")
+	client.httpClient = &http.Client{Transport: transport}
+
+	history, err := NewSegmentsService(client).History(229781, 227615).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(history.Efforts) != 203 {
+		t.Errorf("should gather all the efforts, got %d", len(history.Efforts))
+	}
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("should request 2 pages, got %d", len(transport.requests))
+	}
+
+	req := transport.requests[1]
+	if req.URL.Path != "/api/v3/segments/229781/all_efforts" {
+		t.Errorf("request path incorrect, got %v", req.URL.Path)
+	}
+
+	if req.URL.RawQuery != "athlete_id=227615&page=2&per_page=200" {
+		t.Errorf("request query incorrect, got %v", req.URL.RawQuery)
+	}
+
+	if history.SegmentId != 229781 || history.AthleteId != 227615 {
+		t.Errorf("history ids incorrect, got %d %d", history.SegmentId, history.AthleteId)
+	}
+
+	// errors
+	_, err = NewSegmentsService(NewStubResponseClient("bad json")).History(1, 2).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+}
+
+func TestSegmentHistoryStats(t *testing.T) {
+	efforts := []*SegmentEffortSummary{
+		newTestHistoryEffort(3, 320),
+		newTestHistoryEffort(1, 360),
+		newTestHistoryEffort(2, 380),
+		newTestHistoryEffort(4, 300),
+		newTestHistoryEffort(5, 340),
+	}
+
+	history := NewSegmentHistory(229781, 227615, efforts)
+
+	for i, e := range history.Efforts {
+		if e.Id != int64(i+1) {
+			t.Errorf("efforts should be in date order, got %d at %d", e.Id, i)
+		}
+	}
+
+	if len(history.Progression) != 3 {
+		t.Fatalf("incorrect progression, got %d", len(history.Progression))
+	}
+
+	for i, id := range []int64{1, 3, 4} {
+		if history.Progression[i].Id != id {
+			t.Errorf("progression %d incorrect, got %d", i, history.Progression[i].Id)
+		}
+	}
+
+	if history.Best().ElapsedTime != 300 {
+		t.Errorf("best incorrect, got %d", history.Best().ElapsedTime)
+	}
+
+	if history.MedianElapsedTime != 340 {
+		t.Errorf("median incorrect, got %v", history.MedianElapsedTime)
+	}
+
+	if history.InterquartileRange != 40 {
+		t.Errorf("interquartile range incorrect, got %v", history.InterquartileRange)
+	}
+
+	if v := history.StandardDeviation; v < 28.28 || v > 28.29 {
+		t.Errorf("standard deviation incorrect, got %v", v)
+	}
+
+	if NewSegmentHistory(1, 2, nil).Best() != nil {
+		t.Error("empty history should have no best")
+	}
+}
+
+func TestSegmentHistoryRanks(t *testing.T) {
+	history := NewSegmentHistory(229781, 227615, []*SegmentEffortSummary{
+		newTestHistoryEffort(1, 400),
+		newTestHistoryEffort(2, 330),
+		newTestHistoryEffort(3, 290),
+	})
+
+	leaderboard := &SegmentLeaderboard{EntryCount: 100}
+	for i, time := range []int{250, 280, 290, 300, 320, 340, 360} {
+		entry := &SegmentLeaderboardEntry{AthleteId: int64(i + 1), ElapsedTime: time, Rank: i + 1}
+		leaderboard.Entries = append(leaderboard.Entries, entry)
+	}
+
+	// the athlete's own best
+	leaderboard.Entries[2].AthleteId = 227615
+
+	ranks := history.Ranks(leaderboard)
+	if len(ranks) != 3 {
+		t.Fatalf("incorrect number of ranks, got %d", len(ranks))
+	}
+
+	expected := []SegmentHistoryRank{
+		{history.Progression[0], 0, 0}, // slower than the loaded entries
+		{history.Progression[1], 5, 0}, // after 250, 280, 300 and 320
+		{history.Progression[2], 3, -2},
+	}
+
+	for i, r := range ranks {
+		if *r != expected[i] {
+			t.Errorf("rank %d incorrect, got %d delta %d", i, r.Rank, r.Delta)
+		}
+	}
+
+	// the whole leaderboard
+	leaderboard.EntryCount = 7
+	if r := history.Ranks(leaderboard)[0]; r.Rank != 7 {
+		t.Errorf("rank incorrect, got %d", r.Rank)
+	}
+}
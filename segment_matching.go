@@ -0,0 +1,188 @@
+package strava
+
+import (
+	"errors"
+	"math"
+)
+
+type SegmentMatchOptions struct {
+	// Efforts must start and finish within this many meters of the segment's start and end.
+	EndpointRadius float64
+
+	// Every sample of an effort must be within this many meters of the segment's polyline.
+	CorridorWidth float64
+
+	// Fraction the distance of an effort may differ from the segment's distance.
+	DistanceTolerance float64
+}
+
+// DefaultSegmentMatchOptions are used by MatchSegment if no options are provided.
+var DefaultSegmentMatchOptions = SegmentMatchOptions{
+	EndpointRadius:    25,
+	CorridorWidth:     50,
+	DistanceTolerance: 0.1,
+}
+
+// MatchSegment finds every traversal of the segment in the streams, without
+// waiting for Strava's matcher, eg. for private or not yet uploaded activities.
+// Efforts start and end at the samples closest to the segment's start and end locations
+// and have the Segment, Name, indexes, distance, times and averages set.
+// Requires the Time and Location streams. Segment.Map.Polyline is used if available,
+// otherwise a straight line between the start and end locations.
+func (s *StreamSet) MatchSegment(segment *SegmentDetailed, options ...SegmentMatchOptions) ([]*SegmentEffortSummary, error) {
+	opts := DefaultSegmentMatchOptions
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	if s.Time == nil || s.Location == nil || len(s.Location.Data) != len(s.Time.Data) {
+		return nil, errors.New("time and location streams required")
+	}
+
+	line := segment.Map.Polyline.Decode()
+	if len(line) < 2 {
+		line = [][2]float64{segment.StartLocation, segment.EndLocation}
+	}
+
+	start, end := segment.StartLocation, segment.EndLocation
+	if start.IsNullIsland() {
+		start = line[0]
+	}
+
+	if end.IsNullIsland() {
+		end = line[len(line)-1]
+	}
+
+	if start.IsNullIsland() || end.IsNullIsland() {
+		return nil, errors.New("segment polyline or start and end locations required")
+	}
+
+	length := segment.Distance
+	if length <= 0 {
+		for i := 1; i < len(line); i++ {
+			length += Location(line[i-1]).DistanceTo(line[i])
+		}
+	}
+
+	samples := make([]int, 0, len(s.Time.Data))
+	for _, i := range s.validTimeIndexes() {
+		if !Location(s.Location.Data[i]).IsNullIsland() {
+			samples = append(samples, i)
+		}
+	}
+
+	m := &segmentMatcher{
+		streams:   s,
+		samples:   samples,
+		distances: s.cumulativeDistances(samples),
+		line:      line,
+		end:       end,
+		length:    length,
+		options:   opts,
+	}
+
+	efforts := make([]*SegmentEffortSummary, 0)
+	for j := 0; j < len(samples); {
+		if start.DistanceTo(m.location(j)) > opts.EndpointRadius {
+			j++
+			continue
+		}
+
+		// start from the closest sample of the pass
+		first, closest := j, math.Inf(1)
+		for ; j < len(samples) && start.DistanceTo(m.location(j)) <= opts.EndpointRadius; j++ {
+			if d := start.DistanceTo(m.location(j)); d < closest {
+				first, closest = j, d
+			}
+		}
+
+		last := m.traverse(first)
+		if last < 0 {
+			continue
+		}
+
+		efforts = append(efforts, s.segmentEffort(segment, samples[first], samples[last]))
+
+		// the end of a loop segment can also be the start of the next effort
+		j = last
+	}
+
+	return efforts, nil
+}
+
+/*********************************************************/
+
+type segmentMatcher struct {
+	streams   *StreamSet
+	samples   []int
+	distances []float64
+	line      [][2]float64
+	end       Location
+	length    float64
+	options   SegmentMatchOptions
+}
+
+func (m *segmentMatcher) location(j int) Location {
+	return m.streams.Location.Data[m.samples[j]]
+}
+
+// traverse follows the segment from the sample, returning the
+// sample it finishes at or -1 if the activity leaves the segment first.
+func (m *segmentMatcher) traverse(first int) int {
+	minimum := m.length * (1 - m.options.DistanceTolerance)
+	maximum := m.length * (1 + m.options.DistanceTolerance)
+
+	for j := first + 1; j < len(m.samples); j++ {
+		l := m.location(j)
+		if l.DistanceToLine(m.line) > m.options.CorridorWidth {
+			return -1
+		}
+
+		traveled := m.distances[j] - m.distances[first]
+		if traveled > maximum {
+			return -1
+		}
+
+		// distance is required so loops don't finish where they start
+		if traveled < minimum || m.end.DistanceTo(l) > m.options.EndpointRadius {
+			continue
+		}
+
+		// finish at the closest sample of the pass
+		last, closest := j, m.end.DistanceTo(l)
+		for j++; j < len(m.samples); j++ {
+			l = m.location(j)
+			d := m.end.DistanceTo(l)
+			if d > m.options.EndpointRadius || m.distances[j]-m.distances[first] > maximum {
+				break
+			}
+
+			if d < closest {
+				last, closest = j, d
+			}
+		}
+
+		return last
+	}
+
+	return -1
+}
+
+func (s *StreamSet) segmentEffort(segment *SegmentDetailed, start, end int) *SegmentEffortSummary {
+	summary, _ := s.slice(start, end).Summary()
+
+	effort := &SegmentEffortSummary{}
+	effort.Name = segment.Name
+	effort.Segment = segment.SegmentSummary
+	effort.Distance = summary.Distance
+	effort.MovingTime = summary.MovingTime
+	effort.ElapsedTime = summary.ElapsedTime
+	effort.StartIndex = start
+	effort.EndIndex = end
+	effort.AverageCadence = summary.AverageCadence
+	effort.AveragePower = summary.AveragePower
+	effort.AverageHeartrate = summary.AverageHeartrate
+	effort.MaximumHeartrate = summary.MaximumHeartrate
+
+	return effort
+}
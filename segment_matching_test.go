@@ -0,0 +1,104 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestSegmentStreamSet() *StreamSet {
+	// north, south and north again, about 11 meters a second
+	set := &StreamSet{
+		Time:      &IntegerStream{Data: make([]int, 0)},
+		Location:  &LocationStream{Data: make([][2]float64, 0)},
+		HeartRate: &IntegerStream{Data: make([]int, 0)},
+	}
+
+	for i := 0; i <= 180; i++ {
+		offset := i % 120
+		if offset > 60 {
+			offset = 120 - offset
+		}
+
+		set.Time.Data = append(set.Time.Data, i)
+		set.Location.Data = append(set.Location.Data, [2]float64{37.8 + float64(offset)*0.0001, -122.4})
+		set.HeartRate.Data = append(set.HeartRate.Data, 100+i)
+	}
+
+	return set
+}
+
+func TestStreamSetMatchSegment(t *testing.T) {
+	set := newTestSegmentStreamSet()
+
+	segment := &SegmentDetailed{}
+	segment.Id = 123
+	segment.Name = "North"
+	segment.StartLocation = Location{37.801, -122.4}
+	segment.EndLocation = Location{37.805, -122.4}
+	segment.Map.Polyline = EncodePolyline([][2]float64{
+		{37.801, -122.4}, {37.802, -122.4}, {37.803, -122.4}, {37.804, -122.4}, {37.805, -122.4},
+	})
+	segment.Distance = segment.StartLocation.DistanceTo(segment.EndLocation)
+
+	efforts, err := set.MatchSegment(segment)
+	if err != nil {
+		t.Fatalf("match error: %v", err)
+	}
+
+	if len(efforts) != 2 {
+		t.Fatalf("incorrect number of efforts, got %d", len(efforts))
+	}
+
+	expected := [][2]int{{10, 50}, {130, 170}}
+	for i, e := range efforts {
+		if e.StartIndex != expected[i][0] || e.EndIndex != expected[i][1] {
+			t.Errorf("effort %d indexes incorrect, got %d to %d", i, e.StartIndex, e.EndIndex)
+		}
+
+		if e.ElapsedTime != 40 {
+			t.Errorf("effort %d elapsed time incorrect, got %d", i, e.ElapsedTime)
+		}
+
+		if math.Abs(e.Distance-segment.Distance) > 1 {
+			t.Errorf("effort %d distance incorrect, got %v", i, e.Distance)
+		}
+
+		if e.Segment.Id != 123 || e.Name != "North" {
+			t.Errorf("effort %d segment not set", i)
+		}
+
+		if e.AverageHeartrate != float64(100+e.StartIndex)+20.5 {
+			t.Errorf("effort %d average heartrate incorrect, got %v", i, e.AverageHeartrate)
+		}
+	}
+
+	// southbound only
+	segment.StartLocation, segment.EndLocation = segment.EndLocation, segment.StartLocation
+	segment.Map.Polyline = ""
+
+	efforts, err = set.MatchSegment(segment)
+	if err != nil {
+		t.Fatalf("match error: %v", err)
+	}
+
+	if len(efforts) != 1 || efforts[0].StartIndex != 70 || efforts[0].EndIndex != 110 {
+		t.Errorf("southbound effort incorrect, got %d efforts", len(efforts))
+	}
+
+	// a much longer segment with the same end points
+	segment.Distance *= 2
+	efforts, err = set.MatchSegment(segment)
+	if err != nil {
+		t.Fatalf("match error: %v", err)
+	}
+
+	if len(efforts) != 0 {
+		t.Errorf("should not match, got %d efforts", len(efforts))
+	}
+
+	// errors
+	set.Location = nil
+	if _, err := set.MatchSegment(segment); err == nil {
+		t.Error("should return error without location stream")
+	}
+}
@@ -12,15 +12,12 @@ type SegmentDetailed struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
-	TotalElevationGain float64 `json:"total_elevation_gain"`
-	Map                struct {
-		Id       string   `json:"id"`
-		Polyline Polyline `json:"polyline"`
-	} `json:"map"`
-	EffortCount  int  `json:"effort_count"`
-	AthleteCount int  `json:"athlete_count"`
-	StarCount    int  `json:"star_count"`
-	Hazardous    bool `json:"hazardous"`
+	TotalElevationGain float64     `json:"total_elevation_gain"`
+	Map                PolylineMap `json:"map"`
+	EffortCount        int         `json:"effort_count"`
+	AthleteCount       int         `json:"athlete_count"`
+	StarCount          int         `json:"star_count"`
+	Hazardous          bool        `json:"hazardous"`
 }
 
 type SegmentSummary struct {
@@ -44,15 +41,18 @@ type SegmentSummary struct {
 
 type PersonalSegmentSummary struct {
 	SegmentSummary
-	AthletePR struct {
-		Id             int64     `json:"id"`
-		ElapsedTime    int       `json:"elapsed_time"`
-		Distance       float64   `json:"distance"`
-		StartDate      time.Time `json:"start_date"`
-		StartDateLocal time.Time `json:"start_date_local"`
-		IsKOM          bool      `json:"is_kom"`
-	} `json:"athlete_pr_effort"`
-	StarredDate time.Time `json:"starred_date"`
+	AthletePR   AthletePREffort `json:"athlete_pr_effort"`
+	StarredDate time.Time       `json:"starred_date"`
+}
+
+// AthletePREffort is the athlete's personal record on a starred segment.
+type AthletePREffort struct {
+	Id             int64     `json:"id"`
+	ElapsedTime    int       `json:"elapsed_time"`
+	Distance       float64   `json:"distance"`
+	StartDate      time.Time `json:"start_date"`
+	StartDateLocal time.Time `json:"start_date_local"`
+	IsKOM          bool      `json:"is_kom"`
 }
 
 type SegmentLeaderboard struct {
@@ -142,6 +142,9 @@ var DateRanges = struct {
 	Today     DateRange
 }{"this_year", "this_month", "this_week", "today"}
 
+// the most entries GetLeaderboard will return per page
+const segmentLeaderboardPerPage = 200
+
 type SegmentsService struct {
 	client *Client
 }
@@ -181,6 +184,38 @@ func (s *SegmentsGetCall) Do() (*SegmentDetailed, error) {
 
 /*********************************************************/
 
+type SegmentsStarCall struct {
+	service *SegmentsService
+	id      int64
+	starred bool
+}
+
+// Star stars or unstars the segment for the current athlete.
+func (s *SegmentsService) Star(segmentId int64, starred bool) *SegmentsStarCall {
+	return &SegmentsStarCall{
+		service: s,
+		id:      segmentId,
+		starred: starred,
+	}
+}
+
+func (c *SegmentsStarCall) Do() (*SegmentDetailed, error) {
+	data, err := c.service.client.run("PUT", fmt.Sprintf("/segments/%d/starred", c.id), map[string]interface{}{"starred": c.starred})
+	if err != nil {
+		return nil, err
+	}
+
+	var segment SegmentDetailed
+	err = json.Unmarshal(data, &segment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &segment, nil
+}
+
+/*********************************************************/
+
 type SegmentsListEffortsCall struct {
 	service *SegmentsService
 	id      int64
@@ -307,6 +342,57 @@ func (c *SegmentsGetLeaderboardCall) Do() (*SegmentLeaderboard, error) {
 	return &leaderboard, nil
 }
 
+// DoAll requests pages until all EntryCount entries of the leaderboard are loaded.
+// Page, PerPage and ContextEntries are ignored.
+func (c *SegmentsGetLeaderboardCall) DoAll() (*SegmentLeaderboard, error) {
+	return c.doPages(0)
+}
+
+// doPages loads pages until at least limit entries are loaded, or all of them if limit is 0.
+func (c *SegmentsGetLeaderboardCall) doPages(limit int) (*SegmentLeaderboard, error) {
+	call := c.service.GetLeaderboard(c.id)
+	for k, v := range c.ops {
+		call.ops[k] = v
+	}
+
+	// Strava adds context entries around the athlete by default, which would repeat entries
+	call.ContextEntries(0)
+
+	all := &SegmentLeaderboard{Entries: make([]*SegmentLeaderboardEntry, 0)}
+	seen := make(map[int64]bool)
+	for page := 1; ; page++ {
+		leaderboard, err := call.Page(page).PerPage(segmentLeaderboardPerPage).Do()
+		if err != nil {
+			return nil, err
+		}
+
+		all.EntryCount = leaderboard.EntryCount
+		for _, entry := range leaderboard.Entries {
+			// by effort, or athlete as each has one entry, if the effort id is missing
+			key := entry.EffortId
+			if key == 0 {
+				key = -entry.AthleteId
+			}
+
+			if !seen[key] {
+				seen[key] = true
+				all.Entries = append(all.Entries, entry)
+			}
+		}
+
+		if len(leaderboard.Entries) == 0 || len(all.Entries) >= all.EntryCount ||
+			(limit > 0 && len(all.Entries) >= limit) {
+			break
+		}
+	}
+
+	if limit > 0 && len(all.Entries) > limit {
+		all.Entries = all.Entries[:limit]
+	}
+
+	return all, nil
+}
+
 /*********************************************************/
 
 type SegmentsExplorerCall struct {
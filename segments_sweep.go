@@ -0,0 +1,185 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// the most segments Explore will return for a bounding box
+const segmentExplorerLimit = 10
+
+// replaced when testing
+var sweepSleep = time.Sleep
+
+type SegmentsSweepCall struct {
+	service           *SegmentsService
+	bounds            Bounds
+	ops               map[string]interface{}
+	maximumDepth      int
+	rateLimitFraction float32
+	channel           chan<- *SegmentExplorerSegment
+}
+
+// Sweep explores a large area by recursively splitting it into quarters until
+// each part returns fewer segments than the Explore limit. Segments are returned once,
+// even if found in more than one part.
+func (s *SegmentsService) Sweep(south, west, north, east float64) *SegmentsSweepCall {
+	return &SegmentsSweepCall{
+		service:           s,
+		bounds:            Bounds{south, west, north, east},
+		ops:               make(map[string]interface{}),
+		maximumDepth:      8,
+		rateLimitFraction: 0.95,
+	}
+}
+
+func (c *SegmentsSweepCall) ActivityType(activityType string) *SegmentsSweepCall {
+	c.ops["activity_type"] = activityType
+	return c
+}
+
+func (c *SegmentsSweepCall) MinimumCategory(cat int) *SegmentsSweepCall {
+	c.ops["min_cat"] = cat
+	return c
+}
+
+func (c *SegmentsSweepCall) MaximumCategory(cat int) *SegmentsSweepCall {
+	c.ops["max_cat"] = cat
+	return c
+}
+
+// MaximumDepth limits how many times the area is split, defaults to 8.
+// Parts at the maximum depth may be missing segments.
+func (c *SegmentsSweepCall) MaximumDepth(depth int) *SegmentsSweepCall {
+	c.maximumDepth = depth
+	return c
+}
+
+// RateLimitFraction sets the fraction of the short term rate limit, see RateLimiting,
+// at which the sweep pauses until the next 15 minute window. Defaults to 0.95.
+// The sweep stops with an error once the daily limit is reached.
+func (c *SegmentsSweepCall) RateLimitFraction(fraction float32) *SegmentsSweepCall {
+	c.rateLimitFraction = fraction
+	return c
+}
+
+// Stream sends each new segment to the channel as it is found.
+// The channel is closed when Do returns.
+func (c *SegmentsSweepCall) Stream(channel chan<- *SegmentExplorerSegment) *SegmentsSweepCall {
+	c.channel = channel
+	return c
+}
+
+// Do runs the sweep, returning all the segments found. On error the segments
+// found so far are returned along with the error.
+func (c *SegmentsSweepCall) Do() ([]*SegmentExplorerSegment, error) {
+	if c.channel != nil {
+		defer close(c.channel)
+	}
+
+	if c.bounds.South >= c.bounds.North || c.bounds.West >= c.bounds.East {
+		return nil, errors.New("invalid sweep bounds")
+	}
+
+	sweep := &segmentsSweep{
+		call:     c,
+		seen:     make(map[int64]bool),
+		segments: make([]*SegmentExplorerSegment, 0),
+	}
+
+	err := sweep.explore(c.bounds, 0)
+	return sweep.segments, err
+}
+
+/*********************************************************/
+
+type segmentsSweep struct {
+	call     *SegmentsSweepCall
+	seen     map[int64]bool
+	segments []*SegmentExplorerSegment
+}
+
+func (s *segmentsSweep) explore(b Bounds, depth int) error {
+	if err := s.waitForRateLimit(); err != nil {
+		return err
+	}
+
+	call := s.call.service.Explore(b.South, b.West, b.North, b.East)
+	for k, v := range s.call.ops {
+		call.ops[k] = v
+	}
+
+	found, err := call.Do()
+	if err != nil {
+		return fmt.Errorf("exploring %f,%f,%f,%f: %v", b.South, b.West, b.North, b.East, err)
+	}
+
+	for _, segment := range found {
+		if s.seen[segment.Id] {
+			continue
+		}
+		s.seen[segment.Id] = true
+
+		s.segments = append(s.segments, segment)
+		if s.call.channel != nil {
+			s.call.channel <- segment
+		}
+	}
+
+	if len(found) < segmentExplorerLimit || depth >= s.call.maximumDepth {
+		return nil
+	}
+
+	center := b.Center()
+	quarters := []Bounds{
+		{b.South, b.West, center[0], center[1]},
+		{b.South, center[1], center[0], b.East},
+		{center[0], b.West, b.North, center[1]},
+		{center[0], center[1], b.North, b.East},
+	}
+
+	for _, q := range quarters {
+		if err := s.explore(q, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForRateLimit sleeps until the next 15 minute rate limit window if the most
+// recent request reached the configured fraction of the short term limit.
+// Returns an error if the daily limit has been reached, as waiting won't help.
+// Usage from an earlier window or UTC day is ignored as Strava has reset it.
+func (s *segmentsSweep) waitForRateLimit() error {
+	RateLimiting.lock.RLock()
+	requestTime := RateLimiting.RequestTime.UTC()
+	limitShort, usageShort := RateLimiting.LimitShort, RateLimiting.UsageShort
+	limitLong, usageLong := RateLimiting.LimitLong, RateLimiting.UsageLong
+	RateLimiting.lock.RUnlock()
+
+	now := time.Now().UTC()
+	if requestTime.IsZero() || requestTime.Before(now.Truncate(24*time.Hour)) {
+		return nil
+	}
+
+	if limitLong > 0 && usageLong >= limitLong {
+		return errors.New("daily rate limit reached")
+	}
+
+	window := requestTime.Truncate(15 * time.Minute)
+	if window.Before(now.Truncate(15 * time.Minute)) {
+		return nil
+	}
+
+	if limitShort == 0 || float32(usageShort)/float32(limitShort) < s.call.rateLimitFraction {
+		return nil
+	}
+
+	if wait := window.Add(15 * time.Minute).Sub(now); wait > 0 {
+		sweepSleep(wait)
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package strava
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exploreTransport returns, at most 10 of, the segments starting within the requested bounds
+type exploreTransport struct {
+	http.Transport
+	segments []*SegmentExplorerSegment
+	requests int
+	header   http.Header
+}
+
+func (t *exploreTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+
+	var b Bounds
+	fmt.Sscanf(req.URL.Query().Get("bounds"), "%f,%f,%f,%f", &b.South, &b.West, &b.North, &b.East)
+
+	found := make([]*SegmentExplorerSegment, 0)
+	for _, s := range t.segments {
+		if b.Contains(s.StartLocation) && len(found) < segmentExplorerLimit {
+			found = append(found, s)
+		}
+	}
+
+	data, _ := json.Marshal(&segmentExplorer{found})
+
+	resp := &http.Response{
+		Status:     http.StatusText(200),
+		StatusCode: 200,
+		Header:     t.header,
+		Body:       ioutil.NopCloser(strings.NewReader(string(data))),
+	}
+
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+
+	return resp, nil
+}
+
+func newExploreClient(count int) (*Client, *exploreTransport) {
+	transport := &exploreTransport{}
+	for i := 0; i < count; i++ {
+		s := &SegmentExplorerSegment{Id: int64(i + 1)}
+		s.StartLocation = Location{37.7 + float64(i%7)*0.01 + 0.001, -122.5 + float64(i/7)*0.01 + 0.001}
+		transport.segments = append(transport.segments, s)
+	}
+
+	c := NewClient("")
+	c.httpClient = &http.Client{Transport: transport}
+
+	return c, transport
+}
+
+func TestSegmentsSweep(t *testing.T) {
+	client, transport := newExploreClient(49)
+	s := NewSegmentsService(client)
+
+	segments, err := s.Sweep(37.7, -122.5, 37.8, -122.4).Do()
+	if err != nil {
+		t.Fatalf("sweep error: %v", err)
+	}
+
+	if len(segments) != 49 {
+		t.Errorf("should find every segment, got %d", len(segments))
+	}
+
+	seen := make(map[int64]bool)
+	for _, segment := range segments {
+		if seen[segment.Id] {
+			t.Errorf("segment %d found twice", segment.Id)
+		}
+		seen[segment.Id] = true
+	}
+
+	if transport.requests <= 1 {
+		t.Errorf("should split the area, got %d requests", transport.requests)
+	}
+
+	// depth limited
+	transport.requests = 0
+	segments, err = s.Sweep(37.7, -122.5, 37.8, -122.4).MaximumDepth(0).Do()
+	if err != nil {
+		t.Fatalf("sweep error: %v", err)
+	}
+
+	if len(segments) != segmentExplorerLimit || transport.requests != 1 {
+		t.Errorf("should not split, got %d segments in %d requests", len(segments), transport.requests)
+	}
+
+	// streamed
+	channel := make(chan *SegmentExplorerSegment)
+	go s.Sweep(37.7, -122.5, 37.8, -122.4).Stream(channel).Do()
+
+	count := 0
+	for range channel {
+		count++
+	}
+
+	if count != 49 {
+		t.Errorf("should stream every segment, got %d", count)
+	}
+
+	// bad bounds
+	if _, err := s.Sweep(37.8, -122.5, 37.7, -122.4).Do(); err == nil {
+		t.Error("should return error for invalid bounds")
+	}
+
+	// errors
+	_, err = NewSegmentsService(NewStubResponseClient("bad json")).Sweep(1, 2, 3, 4).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+}
+
+func TestSegmentsSweepRateLimit(t *testing.T) {
+	client, transport := newExploreClient(3)
+	transport.header = http.Header{
+		"X-Ratelimit-Limit": []string{"600,30000"},
+		"X-Ratelimit-Usage": []string{"599,1000"},
+	}
+
+	waited := time.Duration(0)
+	sweepSleep = func(d time.Duration) { waited += d }
+	defer func() {
+		sweepSleep = time.Sleep
+		RateLimiting.clear()
+	}()
+
+	// the first request sets the rate limit
+	NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).Do()
+	NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).Do()
+
+	if waited <= 0 || waited > 15*time.Minute {
+		t.Errorf("should wait for the next rate limit window, waited %v", waited)
+	}
+
+	waited = 0
+	NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).RateLimitFraction(1.1).Do()
+	if waited != 0 {
+		t.Errorf("should not wait, waited %v", waited)
+	}
+
+	// near the daily limit only
+	RateLimiting.clear()
+	transport.header.Set("X-Ratelimit-Usage", "10,29999")
+	NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).Do()
+	if waited != 0 {
+		t.Errorf("should only wait for the short term limit, waited %v", waited)
+	}
+
+	// daily limit reached
+	transport.header.Set("X-Ratelimit-Usage", "10,30000")
+	NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).Do()
+	if _, err := NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).Do(); err == nil {
+		t.Error("should return error when the daily limit is reached")
+	}
+
+	if waited != 0 {
+		t.Errorf("should not wait for the daily limit, waited %v", waited)
+	}
+
+	// usage from yesterday and the last window is ignored
+	transport.header.Set("X-Ratelimit-Usage", "10,100")
+	for _, ago := range []time.Duration{24 * time.Hour, 15 * time.Minute} {
+		RateLimiting.lock.Lock()
+		RateLimiting.UsageShort, RateLimiting.UsageLong = 600, 30000
+		if ago < 24*time.Hour {
+			RateLimiting.UsageLong = 100
+		}
+		RateLimiting.RequestTime = time.Now().Add(-ago)
+		RateLimiting.lock.Unlock()
+
+		if _, err := NewSegmentsService(client).Sweep(37.7, -122.5, 37.8, -122.4).Do(); err != nil {
+			t.Errorf("should ignore usage from %v ago, got %v", ago, err)
+		}
+
+		if waited != 0 {
+			t.Errorf("should ignore usage from %v ago, waited %v", ago, waited)
+		}
+	}
+}
@@ -252,6 +252,38 @@ func TestSegmentsGetLeaderboard(t *testing.T) {
 	}
 }
 
+func TestSegmentsStar(t *testing.T) {
+	client := NewStubResponseClient(`{"id":229781,"name":"Hawk Hill","starred":true}`)
+	segment, err := NewSegmentsService(client).Star(229781, true).Do()
+
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if segment.Id != 229781 || !segment.Starred {
+		t.Errorf("segment not parsed, got %v", segment)
+	}
+
+	// from here on out just check the request parameters
+	s := NewSegmentsService(newStoreRequestClient())
+
+	s.Star(321, false).Do()
+
+	transport := s.client.httpClient.Transport.(*storeRequestTransport)
+	if transport.request.URL.Path != "/api/v3/segments/321/starred" || transport.request.Method != "PUT" {
+		t.Errorf("request incorrect, got %v %v", transport.request.Method, transport.request.URL.Path)
+	}
+
+	if transport.request.URL.RawQuery != "starred=false" {
+		t.Errorf("request query incorrect, got %v", transport.request.URL.RawQuery)
+	}
+
+	_, err = NewSegmentsService(NewStubResponseClient("bad json")).Star(123, true).Do()
+	if err == nil {
+		t.Error("should return a bad json error")
+	}
+}
+
 func TestSegmentsExplore(t *testing.T) {
 	client := newCassetteClient(testToken, "segment_explore")
 	segments, err := NewSegmentsService(client).Explore(37.674887, -122.595185, 37.840461, -122.280015).Do()
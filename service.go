@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -19,6 +20,7 @@ const timeFormat = "2006-01-02T15:04:05Z"
 type Client struct {
 	token      string
 	httpClient *http.Client
+	baseURL    string
 }
 
 type ErrorHandler func(*http.Response) error
@@ -55,6 +57,13 @@ func NewClient(token string, client ...*http.Client) *Client {
 	return c
 }
 
+// SetBaseURL sends requests to another API server, eg. the URL of a stravatest.Server.
+// Defaults to https://www.strava.com/api/v3.
+func (client *Client) SetBaseURL(baseURL string) *Client {
+	client.baseURL = strings.TrimSuffix(baseURL, "/")
+	return client
+}
+
 // NewStubResponseClient can be used for testing
 // TODO, stub out with an actual response
 func NewStubResponseClient(content string, statusCode ...int) *Client {
@@ -87,6 +96,34 @@ func (t *stubResponseTransport) RoundTrip(req *http.Request) (*http.Response, er
 }
 
 func (client *Client) run(method, path string, params map[string]interface{}) ([]byte, error) {
+	resp, err := client.runResponse(method, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	return checkResponseForErrors(resp)
+}
+
+// runReader is like run but returns the response body unread, for large downloads.
+// The caller must close it.
+func (client *Client) runReader(method, path string, params map[string]interface{}) (io.ReadCloser, error) {
+	resp, err := client.runResponse(method, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode/100 > 2 {
+		defer resp.Body.Close()
+		return nil, defaultErrorHandler(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// runResponse builds and sends the request, returning the response with its body unread.
+func (client *Client) runResponse(method, path string, params map[string]interface{}) (*http.Response, error) {
 	var err error
 
 	values := make(url.Values)
@@ -96,25 +133,23 @@ func (client *Client) run(method, path string, params map[string]interface{}) ([
 
 	var req *http.Request
 	if method == "POST" {
-		req, err = http.NewRequest("POST", basePath+path, strings.NewReader(values.Encode()))
+		req, err = http.NewRequest("POST", client.url(path), strings.NewReader(values.Encode()))
 		if err != nil {
 			return nil, err
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	} else {
-		req, err = http.NewRequest(method, basePath+path+"?"+values.Encode(), nil)
+		req, err = http.NewRequest(method, client.url(path)+"?"+values.Encode(), nil)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	return client.runRequest(req)
+	return client.do(req)
 }
 
 func (client *Client) runRequestWithErrorHandler(req *http.Request, errorHandler ErrorHandler) ([]byte, error) {
-	req.Header.Set("Authorization", "Bearer "+client.token)
-	req.Header.Set("User-Agent", "Strava Golang Library v1")
-	resp, err := client.httpClient.Do(req)
+	resp, err := client.do(req)
 
 	// this was a poor request, maybe strava servers down?
 	if err != nil {
@@ -123,8 +158,6 @@ func (client *Client) runRequestWithErrorHandler(req *http.Request, errorHandler
 
 	defer resp.Body.Close()
 
-	RateLimiting.updateRateLimits(resp)
-
 	return checkResponseForErrorsWithErrorHandler(resp, errorHandler)
 }
 
@@ -132,6 +165,28 @@ func (client *Client) runRequest(req *http.Request) ([]byte, error) {
 	return client.runRequestWithErrorHandler(req, defaultErrorHandler)
 }
 
+// do sends the request with the client's token and updates RateLimiting.
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+client.token)
+	req.Header.Set("User-Agent", "Strava Golang Library v1")
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	RateLimiting.updateRateLimits(resp)
+
+	return resp, nil
+}
+
+func (client *Client) url(path string) string {
+	if client.baseURL == "" {
+		return basePath + path
+	}
+
+	return client.baseURL + path
+}
+
 func checkResponseForErrorsWithErrorHandler(resp *http.Response, errorHandler ErrorHandler) ([]byte, error) {
 	if resp.StatusCode/100 > 2 {
 		return nil, errorHandler(resp)
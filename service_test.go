@@ -138,6 +138,21 @@ func TestRun(t *testing.T) {
 	}
 }
 
+func TestClientSetBaseURL(t *testing.T) {
+	c := newStoreRequestClient().SetBaseURL("http://127.0.0.1:1234/api/v3/")
+	transport := c.httpClient.Transport.(*storeRequestTransport)
+
+	c.run("GET", "/athlete", nil)
+	if u := transport.request.URL.String(); u != "http://127.0.0.1:1234/api/v3/athlete?" {
+		t.Errorf("request url incorrect, got %v", u)
+	}
+
+	NewUploadsService(c).Create(FileDataTypes.GPXGZ, "", strings.NewReader("")).Do()
+	if u := transport.request.URL.String(); u != "http://127.0.0.1:1234/api/v3/uploads" {
+		t.Errorf("request url incorrect, got %v", u)
+	}
+}
+
 func TestCheckResponseForErrors(t *testing.T) {
 	var err error
 	var resp http.Response
@@ -0,0 +1,1188 @@
+package stravatest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/strava/go.strava"
+)
+
+const timeFormat = "2006-01-02T15:04:05Z"
+
+// the most segments Explore returns
+const segmentExplorerLimit = 10
+
+// route dispatches the request by the parts of its path after BasePath.
+// Handlers are called with the server locked, and the id of the athlete making the request.
+func route(s *Server, w http.ResponseWriter, r *http.Request, parts []string, athleteId int64) {
+	// the id in the path, eg. 123 in /activities/123/streams
+	var id int64
+	if len(parts) > 1 {
+		id, _ = parseId(parts[1])
+	}
+
+	switch {
+	case r.Method == "GET" && match(parts, "athlete"):
+		s.getCurrentAthlete(w, r, athleteId)
+	case r.Method == "PUT" && match(parts, "athlete"):
+		s.updateCurrentAthlete(w, r, athleteId)
+	case r.Method == "GET" && match(parts, "athlete", "activities"):
+		s.listAthleteActivities(w, r, athleteId)
+	case r.Method == "GET" && match(parts, "athlete", "clubs"):
+		s.listAthleteClubs(w, r, athleteId)
+	case r.Method == "GET" && match(parts, "athlete", "zones"):
+		s.getAthleteZones(w, r, athleteId)
+	case r.Method == "GET" && match(parts, "athletes", "{id}"):
+		s.getAthlete(w, r, id)
+	case r.Method == "GET" && match(parts, "athletes", "{id}", "activities"):
+		s.listAthleteActivities(w, r, id)
+	case r.Method == "GET" && match(parts, "athletes", "{id}", "koms"):
+		s.listKOMs(w, r, id)
+	case r.Method == "GET" && match(parts, "athletes", "{id}", "routes"):
+		s.listAthleteRoutes(w, r, id, athleteId)
+
+	case r.Method == "POST" && match(parts, "activities"):
+		s.createActivity(w, r, athleteId)
+	case r.Method == "GET" && match(parts, "activities", "{id}"):
+		s.getActivity(w, r, id, athleteId)
+	case r.Method == "PUT" && match(parts, "activities", "{id}"):
+		s.updateActivity(w, r, id, athleteId)
+	case r.Method == "DELETE" && match(parts, "activities", "{id}"):
+		s.deleteActivity(w, r, id, athleteId)
+	case r.Method == "GET" && match(parts, "activities", "{id}", "streams", "*"):
+		s.getStreams(w, r, streamParent{"activities", id}, "Activity", strings.Split(parts[3], ","))
+	case r.Method == "GET" && match(parts, "activities", "{id}", "comments"):
+		s.listComments(w, r, id)
+	case r.Method == "POST" && match(parts, "activities", "{id}", "comments"):
+		s.createComment(w, r, id, athleteId)
+	case r.Method == "DELETE" && match(parts, "activities", "{id}", "comments", "{id}"):
+		s.deleteComment(w, r, id, athleteId, parts[3])
+	case r.Method == "GET" && match(parts, "activities", "{id}", "kudos"):
+		s.listKudos(w, r, id)
+	case (r.Method == "POST" || r.Method == "DELETE") && match(parts, "activities", "{id}", "kudos"):
+		s.kudo(w, r, id, athleteId, r.Method == "POST")
+	case r.Method == "GET" && match(parts, "activities", "{id}", "laps"):
+		s.listLaps(w, r, id)
+	case r.Method == "GET" && match(parts, "activities", "{id}", "zones"):
+		s.listZones(w, r, id)
+
+	case r.Method == "GET" && match(parts, "segments", "explore"):
+		s.exploreSegments(w, r)
+	case r.Method == "GET" && match(parts, "segments", "{id}"):
+		s.getSegment(w, r, id)
+	case r.Method == "PUT" && match(parts, "segments", "{id}", "starred"):
+		s.starSegment(w, r, id)
+	case r.Method == "GET" && match(parts, "segments", "{id}", "all_efforts"):
+		s.listEfforts(w, r, id, athleteId)
+	case r.Method == "GET" && match(parts, "segments", "{id}", "leaderboard"):
+		s.getLeaderboard(w, r, id, athleteId)
+	case r.Method == "GET" && match(parts, "segments", "{id}", "streams", "*"):
+		s.getStreams(w, r, streamParent{"segments", id}, "Segment", strings.Split(parts[3], ","))
+	case r.Method == "GET" && match(parts, "segment_efforts", "{id}"):
+		s.getEffort(w, r, id)
+	case r.Method == "GET" && match(parts, "segment_efforts", "{id}", "streams", "*"):
+		s.getStreams(w, r, streamParent{"segment_efforts", id}, "SegmentEffort", strings.Split(parts[3], ","))
+
+	case r.Method == "GET" && match(parts, "clubs", "{id}"):
+		s.getClub(w, r, id)
+	case r.Method == "GET" && match(parts, "clubs", "{id}", "members"):
+		s.listClubMembers(w, r, id)
+	case r.Method == "GET" && match(parts, "clubs", "{id}", "activities"):
+		s.listClubActivities(w, r, id)
+	case r.Method == "POST" && (match(parts, "clubs", "{id}", "join") || match(parts, "clubs", "{id}", "leave")):
+		s.joinClub(w, r, id, athleteId, parts[2] == "join")
+	case r.Method == "GET" && match(parts, "clubs", "{id}", "group_events"):
+		s.listGroupEvents(w, r, id, athleteId)
+	case r.Method == "GET" && match(parts, "group_events", "{id}"):
+		s.getGroupEvent(w, r, id, athleteId)
+	case (r.Method == "POST" || r.Method == "DELETE") && match(parts, "group_events", "{id}", "rsvps"):
+		s.joinGroupEvent(w, r, id, athleteId, r.Method == "POST")
+	case r.Method == "GET" && match(parts, "group_events", "{id}", "athletes"):
+		s.listGroupEventAthletes(w, r, id)
+
+	case r.Method == "GET" && match(parts, "routes", "{id}"):
+		s.getRoute(w, r, id, athleteId)
+	case r.Method == "GET" && match(parts, "routes", "{id}", "streams"):
+		s.getStreams(w, r, streamParent{"routes", id}, "Route", nil)
+
+	case r.Method == "GET" && match(parts, "gear", "*"):
+		s.getGear(w, r, parts[1])
+
+	case r.Method == "POST" && match(parts, "uploads"):
+		s.createUpload(w, r, athleteId)
+	case r.Method == "GET" && match(parts, "uploads", "{id}"):
+		s.getUpload(w, r, id)
+
+	case r.Method == "POST" && match(parts, "oauth", "deauthorize"):
+		s.deauthorize(w, r, athleteId)
+
+	default:
+		writeNotFound(w, "Resource")
+	}
+}
+
+// match is true if the path parts are the pattern, where {id} matches a number and * anything.
+func match(parts []string, pattern ...string) bool {
+	if len(parts) != len(pattern) {
+		return false
+	}
+
+	for i, p := range pattern {
+		if p == "{id}" {
+			if _, ok := parseId(parts[i]); !ok {
+				return false
+			}
+		} else if p != "*" && p != parts[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*********************************************************/
+
+func (s *Server) getCurrentAthlete(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	writeJSON(w, http.StatusOK, s.athletes[athleteId])
+}
+
+func (s *Server) updateCurrentAthlete(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	athlete := s.athletes[athleteId]
+
+	if v, ok := formValue(r, "city"); ok {
+		athlete.City = v
+	}
+
+	if v, ok := formValue(r, "state"); ok {
+		athlete.State = v
+	}
+
+	if v, ok := formValue(r, "country"); ok {
+		athlete.Country = v
+	}
+
+	if v, ok := formValue(r, "sex"); ok {
+		athlete.Gender = strava.Gender(v)
+	}
+
+	if v, ok := formValue(r, "weight"); ok {
+		athlete.Weight, _ = strconv.ParseFloat(v, 64)
+	}
+
+	athlete.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	writeJSON(w, http.StatusOK, athlete)
+}
+
+func (s *Server) getAthleteZones(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	zones := s.athleteZones[athleteId]
+	if zones == nil {
+		zones = &strava.AthleteZones{}
+	}
+
+	writeJSON(w, http.StatusOK, zones)
+}
+
+func (s *Server) getAthlete(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	athlete := s.athletes[athleteId]
+	if athlete == nil {
+		writeNotFound(w, "Athlete")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, athlete.AthleteSummary)
+}
+
+// listAthleteActivities lists newest first, or oldest first if only after is given like Strava does.
+func (s *Server) listAthleteActivities(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	if s.athletes[athleteId] == nil {
+		writeNotFound(w, "Athlete")
+		return
+	}
+
+	before, _ := strconv.ParseInt(r.Form.Get("before"), 10, 64)
+	after, _ := strconv.ParseInt(r.Form.Get("after"), 10, 64)
+
+	activities := make([]*strava.ActivitySummary, 0)
+	for _, id := range sortedIds(s.activities) {
+		a := s.activities[id]
+		if a.Athlete.Id != athleteId {
+			continue
+		}
+
+		if (before != 0 && a.StartDate.Unix() >= before) || (after != 0 && a.StartDate.Unix() <= after) {
+			continue
+		}
+
+		activities = append(activities, &a.ActivitySummary)
+	}
+
+	ascending := after != 0 && before == 0
+	sort.SliceStable(activities, func(i, j int) bool {
+		if ascending {
+			return activities[i].StartDate.Before(activities[j].StartDate)
+		}
+		return activities[i].StartDate.After(activities[j].StartDate)
+	})
+
+	start, end := page(r, len(activities))
+	writeJSON(w, http.StatusOK, activities[start:end])
+}
+
+func (s *Server) listAthleteClubs(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	clubs := make([]*strava.ClubSummary, 0)
+	for _, id := range s.clubIds() {
+		if s.isMember(id, athleteId) {
+			clubs = append(clubs, &s.clubs[id].ClubSummary)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, clubs)
+}
+
+// listKOMs lists the athlete's efforts that are first on their segment's overall leaderboard.
+func (s *Server) listKOMs(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	if s.athletes[athleteId] == nil {
+		writeNotFound(w, "Athlete")
+		return
+	}
+
+	segmentIds := make([]int64, 0, len(s.segments))
+	for id := range s.segments {
+		segmentIds = append(segmentIds, id)
+	}
+
+	koms := make([]*strava.SegmentEffortSummary, 0)
+	for _, id := range sortIds(segmentIds) {
+		for _, entry := range s.leaderboard(id, "", 0) {
+			if entry.Rank == 1 && entry.AthleteId == athleteId {
+				koms = append(koms, &s.efforts[entry.EffortId].SegmentEffortSummary)
+			}
+		}
+	}
+
+	start, end := page(r, len(koms))
+	writeJSON(w, http.StatusOK, koms[start:end])
+}
+
+/*********************************************************/
+
+func (s *Server) createActivity(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	for _, field := range []string{"name", "start_date_local", "elapsed_time"} {
+		if r.Form.Get(field) == "" {
+			writeError(w, http.StatusBadRequest, "Bad Request", "Activity", field, "missing")
+			return
+		}
+	}
+
+	if r.Form.Get("type") == "" && r.Form.Get("sport_type") == "" {
+		writeError(w, http.StatusBadRequest, "Bad Request", "Activity", "type", "missing")
+		return
+	}
+
+	start, err := time.Parse(timeFormat, r.Form.Get("start_date_local"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", "Activity", "start_date_local", "invalid")
+		return
+	}
+
+	elapsed, err := strconv.Atoi(r.Form.Get("elapsed_time"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", "Activity", "elapsed_time", "invalid")
+		return
+	}
+
+	activity := &strava.ActivityDetailed{}
+	activity.Id = s.id()
+	activity.Athlete.Id = athleteId
+	activity.Name = r.Form.Get("name")
+	activity.Type = strava.ActivityType(r.Form.Get("type"))
+	activity.SportType = strava.ActivityType(r.Form.Get("sport_type"))
+	activity.StartDate = start
+	activity.StartDateLocal = start
+	activity.TimeZone = "(GMT+00:00) UTC"
+	activity.ElapsedTime = elapsed
+	activity.MovingTime = elapsed
+	activity.Description = r.Form.Get("description")
+	activity.Manual = true
+	activity.Distance, _ = strconv.ParseFloat(r.Form.Get("distance"), 64)
+
+	if activity.Type == "" {
+		activity.Type = activity.SportType
+	}
+
+	if activity.SportType == "" {
+		activity.SportType = activity.Type
+	}
+
+	s.activities[activity.Id] = activity
+	writeJSON(w, http.StatusCreated, activity)
+}
+
+// getActivity returns the activity with HasKudoed set for the athlete making the request.
+func (s *Server) getActivity(w http.ResponseWriter, r *http.Request, activityId, athleteId int64) {
+	activity := s.activities[activityId]
+	if activity == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	copied := *activity
+	copied.HasKudoed = containsId(s.kudos[activityId], athleteId)
+
+	writeJSON(w, http.StatusOK, &copied)
+}
+
+func (s *Server) updateActivity(w http.ResponseWriter, r *http.Request, activityId, athleteId int64) {
+	activity := s.activities[activityId]
+	if activity == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	if activity.Athlete.Id != athleteId {
+		writeError(w, http.StatusForbidden, "Forbidden", "Activity", "athlete", "invalid")
+		return
+	}
+
+	if v, ok := formValue(r, "name"); ok {
+		activity.Name = v
+	}
+
+	if v, ok := formValue(r, "description"); ok {
+		activity.Description = v
+	}
+
+	if v, ok := formValue(r, "type"); ok {
+		activity.Type = strava.ActivityType(v)
+	}
+
+	if v, ok := formValue(r, "sport_type"); ok {
+		activity.SportType = strava.ActivityType(v)
+	}
+
+	if v, ok := formValue(r, "private"); ok {
+		activity.Private = v == "1"
+	}
+
+	if v, ok := formValue(r, "commute"); ok {
+		activity.Commute, _ = strconv.ParseBool(v)
+	}
+
+	if v, ok := formValue(r, "trainer"); ok {
+		activity.Trainer, _ = strconv.ParseBool(v)
+	}
+
+	if v, ok := formValue(r, "gear_id"); ok {
+		activity.GearId = v
+	}
+
+	writeJSON(w, http.StatusOK, activity)
+}
+
+func (s *Server) deleteActivity(w http.ResponseWriter, r *http.Request, activityId, athleteId int64) {
+	activity := s.activities[activityId]
+	if activity == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	if activity.Athlete.Id != athleteId {
+		writeError(w, http.StatusForbidden, "Forbidden", "Activity", "athlete", "invalid")
+		return
+	}
+
+	delete(s.activities, activityId)
+	delete(s.streams, streamParent{"activities", activityId})
+	delete(s.comments, activityId)
+	delete(s.kudos, activityId)
+	delete(s.laps, activityId)
+	delete(s.zones, activityId)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getStreams returns the requested streams the object has, unknown types are ignored.
+// Every stream is returned if types is nil, like for routes.
+func (s *Server) getStreams(w http.ResponseWriter, r *http.Request, parent streamParent, resource string, types []string) {
+	streams := s.streams[parent]
+	if streams == nil {
+		writeNotFound(w, resource)
+		return
+	}
+
+	if types == nil {
+		writeJSON(w, http.StatusOK, streams)
+		return
+	}
+
+	requested := &strava.StreamSet{}
+	for _, t := range types {
+		switch strava.StreamType(t) {
+		case strava.StreamTypes.Time:
+			requested.Time = streams.Time
+		case strava.StreamTypes.Location:
+			requested.Location = streams.Location
+		case strava.StreamTypes.Distance:
+			requested.Distance = streams.Distance
+		case strava.StreamTypes.Elevation:
+			requested.Elevation = streams.Elevation
+		case strava.StreamTypes.Speed:
+			requested.Speed = streams.Speed
+		case strava.StreamTypes.HeartRate:
+			requested.HeartRate = streams.HeartRate
+		case strava.StreamTypes.Cadence:
+			requested.Cadence = streams.Cadence
+		case strava.StreamTypes.Power:
+			requested.Power = streams.Power
+		case strava.StreamTypes.Temperature:
+			requested.Temperature = streams.Temperature
+		case strava.StreamTypes.Moving:
+			requested.Moving = streams.Moving
+		case strava.StreamTypes.Grade:
+			requested.Grade = streams.Grade
+		}
+	}
+
+	writeJSON(w, http.StatusOK, requested)
+}
+
+func (s *Server) listComments(w http.ResponseWriter, r *http.Request, activityId int64) {
+	if s.activities[activityId] == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	comments := s.comments[activityId]
+	start, end := page(r, len(comments))
+	writeJSON(w, http.StatusOK, append(make([]*strava.CommentSummary, 0), comments[start:end]...))
+}
+
+func (s *Server) createComment(w http.ResponseWriter, r *http.Request, activityId, athleteId int64) {
+	activity := s.activities[activityId]
+	if activity == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	if r.Form.Get("text") == "" {
+		writeError(w, http.StatusBadRequest, "Bad Request", "Comment", "text", "missing")
+		return
+	}
+
+	comment := &strava.CommentDetailed{}
+	comment.Id = s.id()
+	comment.ActivityId = activityId
+	comment.Text = r.Form.Get("text")
+	comment.Athlete = s.athletes[athleteId].AthleteSummary
+	comment.CreatedAt = time.Now().UTC().Truncate(time.Second)
+
+	s.comments[activityId] = append(s.comments[activityId], &comment.CommentSummary)
+	activity.CommentCount = len(s.comments[activityId])
+
+	writeJSON(w, http.StatusCreated, comment)
+}
+
+// deleteComment removes the comment, only its athlete can delete it.
+func (s *Server) deleteComment(w http.ResponseWriter, r *http.Request, activityId, athleteId int64, commentId string) {
+	activity := s.activities[activityId]
+	if activity == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	id, _ := parseId(commentId)
+	comments := make([]*strava.CommentSummary, 0, len(s.comments[activityId]))
+	for _, c := range s.comments[activityId] {
+		if c.Id != id {
+			comments = append(comments, c)
+			continue
+		}
+
+		if c.Athlete.Id != athleteId {
+			writeError(w, http.StatusForbidden, "Forbidden", "Comment", "athlete", "invalid")
+			return
+		}
+	}
+
+	if len(comments) == len(s.comments[activityId]) {
+		writeNotFound(w, "Comment")
+		return
+	}
+
+	s.comments[activityId] = comments
+	activity.CommentCount = len(comments)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listKudos(w http.ResponseWriter, r *http.Request, activityId int64) {
+	if s.activities[activityId] == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.athleteSummaries(r, s.kudos[activityId]))
+}
+
+// kudo gives or takes back the athlete's kudos.
+func (s *Server) kudo(w http.ResponseWriter, r *http.Request, activityId, athleteId int64, give bool) {
+	activity := s.activities[activityId]
+	if activity == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	if give {
+		s.giveKudos(activityId, athleteId)
+		writeJSON(w, http.StatusCreated, struct{}{})
+		return
+	}
+
+	kudos := make([]int64, 0, len(s.kudos[activityId]))
+	for _, id := range s.kudos[activityId] {
+		if id != athleteId {
+			kudos = append(kudos, id)
+		}
+	}
+
+	s.kudos[activityId] = kudos
+	activity.KudosCount = len(kudos)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) giveKudos(activityId, athleteId int64) {
+	if containsId(s.kudos[activityId], athleteId) {
+		return
+	}
+
+	s.kudos[activityId] = append(s.kudos[activityId], athleteId)
+	if a := s.activities[activityId]; a != nil {
+		a.KudosCount = len(s.kudos[activityId])
+	}
+}
+
+func (s *Server) listLaps(w http.ResponseWriter, r *http.Request, activityId int64) {
+	if s.activities[activityId] == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, append(make([]*strava.LapEffortSummary, 0), s.laps[activityId]...))
+}
+
+func (s *Server) listZones(w http.ResponseWriter, r *http.Request, activityId int64) {
+	if s.activities[activityId] == nil {
+		writeNotFound(w, "Activity")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, append(make([]*strava.ZonesSummary, 0), s.zones[activityId]...))
+}
+
+/*********************************************************/
+
+func (s *Server) getSegment(w http.ResponseWriter, r *http.Request, segmentId int64) {
+	segment := s.segments[segmentId]
+	if segment == nil {
+		writeNotFound(w, "Segment")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, segment)
+}
+
+func (s *Server) starSegment(w http.ResponseWriter, r *http.Request, segmentId int64) {
+	segment := s.segments[segmentId]
+	if segment == nil {
+		writeNotFound(w, "Segment")
+		return
+	}
+
+	starred, _ := strconv.ParseBool(r.Form.Get("starred"))
+	if starred != segment.Starred {
+		if starred {
+			segment.StarCount++
+		} else {
+			segment.StarCount--
+		}
+	}
+
+	segment.Starred = starred
+	writeJSON(w, http.StatusOK, segment)
+}
+
+// exploreSegments returns up to 10 segments starting in the bounds, filtered by
+// activity_type, riding or running, and min_cat and max_cat.
+func (s *Server) exploreSegments(w http.ResponseWriter, r *http.Request) {
+	bounds := strings.Split(r.Form.Get("bounds"), ",")
+	if len(bounds) != 4 {
+		writeError(w, http.StatusBadRequest, "Bad Request", "Segment", "bounds", "invalid")
+		return
+	}
+
+	var b [4]float64
+	for i := range bounds {
+		var err error
+		if b[i], err = strconv.ParseFloat(bounds[i], 64); err != nil {
+			writeError(w, http.StatusBadRequest, "Bad Request", "Segment", "bounds", "invalid")
+			return
+		}
+	}
+
+	activityType := map[string]strava.ActivityType{
+		"riding":  strava.ActivityTypes.Ride,
+		"running": strava.ActivityTypes.Run,
+	}[r.Form.Get("activity_type")]
+
+	minimum, maximum := 0, 5
+	if v, ok := formValue(r, "min_cat"); ok {
+		minimum, _ = strconv.Atoi(v)
+	}
+
+	if v, ok := formValue(r, "max_cat"); ok {
+		maximum, _ = strconv.Atoi(v)
+	}
+
+	ids := make([]int64, 0, len(s.segments))
+	for id := range s.segments {
+		ids = append(ids, id)
+	}
+
+	found := make([]*strava.SegmentExplorerSegment, 0)
+	for _, id := range sortIds(ids) {
+		segment := s.segments[id]
+		start := segment.StartLocation
+
+		if start[0] < b[0] || start[1] < b[1] || start[0] > b[2] || start[1] > b[3] {
+			continue
+		}
+
+		if (activityType != "" && segment.ActivityType != activityType) ||
+			segment.ClimbCategory.Id() < minimum || segment.ClimbCategory.Id() > maximum {
+			continue
+		}
+
+		found = append(found, &strava.SegmentExplorerSegment{
+			Id:                  segment.Id,
+			Name:                segment.Name,
+			ClimbCategory:       segment.ClimbCategory,
+			AverageGrade:        segment.AverageGrade,
+			StartLocation:       segment.StartLocation,
+			EndLocation:         segment.EndLocation,
+			ElevationDifference: segment.ElevationHigh - segment.ElevationLow,
+			Distance:            segment.Distance,
+			Polyline:            segment.Map.Polyline,
+		})
+
+		if len(found) == segmentExplorerLimit {
+			break
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"segments": found})
+}
+
+// listEfforts lists the efforts of athlete_id, or the athlete making the request,
+// on the segment in order, optionally between start_date_local and end_date_local.
+func (s *Server) listEfforts(w http.ResponseWriter, r *http.Request, segmentId, athleteId int64) {
+	if s.segments[segmentId] == nil {
+		writeNotFound(w, "Segment")
+		return
+	}
+
+	if v, ok := formValue(r, "athlete_id"); ok {
+		athleteId, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	from, _ := time.Parse(timeFormat, r.Form.Get("start_date_local"))
+	to, _ := time.Parse(timeFormat, r.Form.Get("end_date_local"))
+
+	efforts := make([]*strava.SegmentEffortSummary, 0)
+	for _, id := range s.effortIds(segmentId) {
+		e := s.efforts[id]
+		if e.Athlete.Id != athleteId {
+			continue
+		}
+
+		if (!from.IsZero() && e.StartDateLocal.Before(from)) || (!to.IsZero() && e.StartDateLocal.After(to)) {
+			continue
+		}
+
+		efforts = append(efforts, &e.SegmentEffortSummary)
+	}
+
+	sort.SliceStable(efforts, func(i, j int) bool {
+		return efforts[i].StartDateLocal.Before(efforts[j].StartDateLocal)
+	})
+
+	start, end := page(r, len(efforts))
+	writeJSON(w, http.StatusOK, efforts[start:end])
+}
+
+// getLeaderboard returns a page of the leaderboard, filtered by gender and club_id.
+// Like Strava, context_entries, 2 by default, adds the entries around the athlete
+// making the request if they aren't on the page.
+func (s *Server) getLeaderboard(w http.ResponseWriter, r *http.Request, segmentId, athleteId int64) {
+	if s.segments[segmentId] == nil {
+		writeNotFound(w, "Segment")
+		return
+	}
+
+	clubId, _ := strconv.ParseInt(r.Form.Get("club_id"), 10, 64)
+	entries := s.leaderboard(segmentId, strava.Gender(r.Form.Get("gender")), clubId)
+
+	context := 2
+	if v, ok := formValue(r, "context_entries"); ok {
+		context, _ = strconv.Atoi(v)
+	}
+
+	start, end := page(r, len(entries))
+	leaderboard := &strava.SegmentLeaderboard{
+		EntryCount: len(entries),
+		Entries:    append(make([]*strava.SegmentLeaderboardEntry, 0), entries[start:end]...),
+	}
+
+	for i, entry := range entries {
+		if entry.AthleteId != athleteId || context <= 0 || (i >= start && i < end) {
+			continue
+		}
+
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(entries) && (j < start || j >= end) {
+				leaderboard.Entries = append(leaderboard.Entries, entries[j])
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, leaderboard)
+}
+
+// leaderboard returns each athlete's fastest effort on the segment in order,
+// optionally only of a gender or the members of a club. Equal times share a rank.
+func (s *Server) leaderboard(segmentId int64, gender strava.Gender, clubId int64) []*strava.SegmentLeaderboardEntry {
+	best := make(map[int64]*strava.SegmentEffortDetailed)
+	for _, id := range s.effortIds(segmentId) {
+		e := s.efforts[id]
+		if b := best[e.Athlete.Id]; b == nil || e.ElapsedTime < b.ElapsedTime {
+			best[e.Athlete.Id] = e
+		}
+	}
+
+	entries := make([]*strava.SegmentLeaderboardEntry, 0, len(best))
+	for athleteId, e := range best {
+		athlete := s.athletes[athleteId]
+		if athlete == nil {
+			athlete = &strava.AthleteDetailed{}
+		}
+
+		if (gender != "" && athlete.Gender != gender) || (clubId != 0 && !s.isMember(clubId, athleteId)) {
+			continue
+		}
+
+		entries = append(entries, &strava.SegmentLeaderboardEntry{
+			AthleteName:      strings.TrimSpace(athlete.FirstName + " " + athlete.LastName),
+			AthleteId:        athleteId,
+			AthleteGender:    athlete.Gender,
+			AthleteProfile:   athlete.Profile,
+			AverageHeartrate: e.AverageHeartrate,
+			AveragePower:     e.AveragePower,
+			Distance:         e.Distance,
+			ElapsedTime:      e.ElapsedTime,
+			MovingTime:       e.MovingTime,
+			StartDate:        e.StartDate,
+			StartDateLocal:   e.StartDateLocal,
+			ActivityId:       e.Activity.Id,
+			EffortId:         e.Id,
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].ElapsedTime != entries[j].ElapsedTime {
+			return entries[i].ElapsedTime < entries[j].ElapsedTime
+		}
+		return entries[i].EffortId < entries[j].EffortId
+	})
+
+	for i, entry := range entries {
+		entry.Rank = i + 1
+		if i > 0 && entry.ElapsedTime == entries[i-1].ElapsedTime {
+			entry.Rank = entries[i-1].Rank
+		}
+	}
+
+	return entries
+}
+
+func (s *Server) getEffort(w http.ResponseWriter, r *http.Request, effortId int64) {
+	effort := s.efforts[effortId]
+	if effort == nil {
+		writeNotFound(w, "SegmentEffort")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, effort)
+}
+
+// effortIds returns the ids of the efforts on the segment in order.
+func (s *Server) effortIds(segmentId int64) []int64 {
+	ids := make([]int64, 0)
+	for id, e := range s.efforts {
+		if e.Segment.Id == segmentId {
+			ids = append(ids, id)
+		}
+	}
+
+	return sortIds(ids)
+}
+
+/*********************************************************/
+
+func (s *Server) getClub(w http.ResponseWriter, r *http.Request, clubId int64) {
+	club := s.clubs[clubId]
+	if club == nil {
+		writeNotFound(w, "Club")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, club)
+}
+
+func (s *Server) listClubMembers(w http.ResponseWriter, r *http.Request, clubId int64) {
+	if s.clubs[clubId] == nil {
+		writeNotFound(w, "Club")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.athleteSummaries(r, s.clubMembers[clubId]))
+}
+
+// listClubActivities lists the activities of the members, newest first.
+func (s *Server) listClubActivities(w http.ResponseWriter, r *http.Request, clubId int64) {
+	if s.clubs[clubId] == nil {
+		writeNotFound(w, "Club")
+		return
+	}
+
+	activities := make([]*strava.ActivitySummary, 0)
+	for _, id := range sortedIds(s.activities) {
+		if a := s.activities[id]; s.isMember(clubId, a.Athlete.Id) {
+			activities = append(activities, &a.ActivitySummary)
+		}
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].StartDate.After(activities[j].StartDate)
+	})
+
+	start, end := page(r, len(activities))
+	writeJSON(w, http.StatusOK, activities[start:end])
+}
+
+// joinClub adds or removes the athlete, joining private clubs is pending and doesn't add them.
+func (s *Server) joinClub(w http.ResponseWriter, r *http.Request, clubId, athleteId int64, join bool) {
+	club := s.clubs[clubId]
+	if club == nil {
+		writeNotFound(w, "Club")
+		return
+	}
+
+	members := make([]int64, 0, len(s.clubMembers[clubId]))
+	for _, id := range s.clubMembers[clubId] {
+		if id != athleteId {
+			members = append(members, id)
+		}
+	}
+
+	membership := &strava.ClubMembership{Success: true}
+	if join && club.Private {
+		membership.Membership = "pending"
+	} else if join {
+		members = append(members, athleteId)
+		membership.Active = true
+		membership.Membership = "member"
+	}
+
+	s.clubMembers[clubId] = members
+	club.MemberCount = len(members)
+
+	writeJSON(w, http.StatusOK, membership)
+}
+
+// listGroupEvents lists the club's events, only those with an occurrence
+// still to come if upcoming is true.
+func (s *Server) listGroupEvents(w http.ResponseWriter, r *http.Request, clubId, athleteId int64) {
+	if s.clubs[clubId] == nil {
+		writeNotFound(w, "Club")
+		return
+	}
+
+	upcoming, _ := strconv.ParseBool(r.Form.Get("upcoming"))
+	now := time.Now()
+
+	ids := make([]int64, 0)
+	for id, e := range s.groupEvents {
+		if e.ClubId == clubId {
+			ids = append(ids, id)
+		}
+	}
+
+	events := make([]*strava.GroupEvent, 0)
+	for _, id := range sortIds(ids) {
+		event := s.groupEvent(id, athleteId)
+
+		if upcoming {
+			next := false
+			for _, t := range event.UpcomingOccurrences {
+				next = next || t.After(now)
+			}
+
+			if !next {
+				continue
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	writeJSON(w, http.StatusOK, events)
+}
+
+func (s *Server) getGroupEvent(w http.ResponseWriter, r *http.Request, eventId, athleteId int64) {
+	if s.groupEvents[eventId] == nil {
+		writeNotFound(w, "GroupEvent")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.groupEvent(eventId, athleteId))
+}
+
+// joinGroupEvent adds or removes the athlete from the event's attendees.
+func (s *Server) joinGroupEvent(w http.ResponseWriter, r *http.Request, eventId, athleteId int64, join bool) {
+	if s.groupEvents[eventId] == nil {
+		writeNotFound(w, "GroupEvent")
+		return
+	}
+
+	athletes := make([]int64, 0, len(s.rsvps[eventId]))
+	for _, id := range s.rsvps[eventId] {
+		if id != athleteId {
+			athletes = append(athletes, id)
+		}
+	}
+
+	if join {
+		athletes = append(athletes, athleteId)
+	}
+
+	s.rsvps[eventId] = athletes
+	writeJSON(w, http.StatusOK, map[string]bool{"joined": join})
+}
+
+func (s *Server) listGroupEventAthletes(w http.ResponseWriter, r *http.Request, eventId int64) {
+	if s.groupEvents[eventId] == nil {
+		writeNotFound(w, "GroupEvent")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.athleteSummaries(r, s.rsvps[eventId]))
+}
+
+// groupEvent returns a copy of the event with Joined set for the athlete.
+func (s *Server) groupEvent(eventId, athleteId int64) *strava.GroupEvent {
+	event := *s.groupEvents[eventId]
+	event.Joined = containsId(s.rsvps[eventId], athleteId)
+
+	return &event
+}
+
+func (s *Server) isMember(clubId, athleteId int64) bool {
+	return containsId(s.clubMembers[clubId], athleteId)
+}
+
+func (s *Server) clubIds() []int64 {
+	ids := make([]int64, 0, len(s.clubs))
+	for id := range s.clubs {
+		ids = append(ids, id)
+	}
+
+	return sortIds(ids)
+}
+
+/*********************************************************/
+
+// getRoute returns the route, private routes only to their athlete.
+func (s *Server) getRoute(w http.ResponseWriter, r *http.Request, routeId, athleteId int64) {
+	route := s.routes[routeId]
+	if route == nil || (route.Private && route.Athlete.Id != athleteId) {
+		writeNotFound(w, "Route")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, route)
+}
+
+// listAthleteRoutes lists the athlete's routes in order, and their private routes if they are making the request.
+func (s *Server) listAthleteRoutes(w http.ResponseWriter, r *http.Request, athleteId, requestAthleteId int64) {
+	if s.athletes[athleteId] == nil {
+		writeNotFound(w, "Athlete")
+		return
+	}
+
+	ids := make([]int64, 0)
+	for id, route := range s.routes {
+		if route.Athlete.Id == athleteId && (!route.Private || athleteId == requestAthleteId) {
+			ids = append(ids, id)
+		}
+	}
+
+	routes := make([]*strava.RouteSummary, 0, len(ids))
+	for _, id := range sortIds(ids) {
+		routes = append(routes, &s.routes[id].RouteSummary)
+	}
+
+	start, end := page(r, len(routes))
+	writeJSON(w, http.StatusOK, routes[start:end])
+}
+
+func (s *Server) getGear(w http.ResponseWriter, r *http.Request, gearId string) {
+	gear := s.gear[gearId]
+	if gear == nil {
+		writeNotFound(w, "Gear")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gear)
+}
+
+/*********************************************************/
+
+// createUpload stores the upload as processing, the activity is ready the first time the upload is checked.
+func (s *Server) createUpload(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	if r.MultipartForm == nil || len(r.MultipartForm.File["file"]) == 0 {
+		writeJSON(w, http.StatusBadRequest, &strava.UploadSummary{Error: "Empty file."})
+		return
+	}
+
+	if r.FormValue("data_type") == "" {
+		writeJSON(w, http.StatusBadRequest, &strava.UploadSummary{Error: "data_type is missing."})
+		return
+	}
+
+	upload := &strava.UploadDetailed{}
+	upload.Id = s.id()
+	upload.ExternalId = r.FormValue("external_id")
+	upload.Status = "Your activity is still being processed."
+
+	if upload.ExternalId == "" {
+		upload.ExternalId = r.MultipartForm.File["file"][0].Filename
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	activity := &strava.ActivityDetailed{}
+	activity.Id = s.id()
+	activity.UploadId = upload.Id
+	activity.ExternalId = upload.ExternalId
+	activity.Athlete.Id = athleteId
+	activity.Name = r.FormValue("name")
+	activity.Description = r.FormValue("description")
+	activity.Type = strava.ActivityType(r.FormValue("activity_type"))
+	activity.StartDate = now
+	activity.StartDateLocal = now
+	activity.TimeZone = "(GMT+00:00) UTC"
+	activity.Private = r.FormValue("private") == "1"
+	activity.Trainer = r.FormValue("trainer") == "1"
+
+	if activity.Name == "" {
+		activity.Name = "Uploaded Activity"
+	}
+
+	if activity.Type == "" {
+		activity.Type = strava.ActivityTypes.Ride
+	}
+	activity.SportType = activity.Type
+
+	s.uploads[upload.Id] = upload
+	s.uploadActivities[upload.Id] = activity.Id
+	s.activities[activity.Id] = activity
+
+	writeJSON(w, http.StatusCreated, upload)
+}
+
+func (s *Server) getUpload(w http.ResponseWriter, r *http.Request, uploadId int64) {
+	upload := s.uploads[uploadId]
+	if upload == nil {
+		writeNotFound(w, "Upload")
+		return
+	}
+
+	if upload.ActivityId == 0 && upload.Error == "" {
+		upload.ActivityId = s.uploadActivities[uploadId]
+		upload.Status = "Your activity is ready."
+	}
+
+	writeJSON(w, http.StatusOK, upload)
+}
+
+/*********************************************************/
+
+// exchangeToken exchanges an AuthorizationCode for the athlete's token.
+// The client id and secret are not checked.
+func (s *Server) exchangeToken(w http.ResponseWriter, r *http.Request) {
+	athleteId, ok := s.codes[r.Form.Get("code")]
+	if !ok || s.athletes[athleteId] == nil {
+		writeError(w, http.StatusBadRequest, "Bad Request", "RequestToken", "code", "invalid")
+		return
+	}
+
+	delete(s.codes, r.Form.Get("code"))
+
+	writeJSON(w, http.StatusOK, &strava.AuthorizationResponse{
+		AccessToken: s.authorize(athleteId),
+		Athlete:     *s.athletes[athleteId],
+	})
+}
+
+// deauthorize revokes the token of the request.
+func (s *Server) deauthorize(w http.ResponseWriter, r *http.Request, athleteId int64) {
+	token := s.token(athleteId)
+	delete(s.tokens, token)
+
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": token})
+}
+
+/*********************************************************/
+
+// athleteSummaries returns a page of the athletes, skipping any that aren't stored.
+func (s *Server) athleteSummaries(r *http.Request, athleteIds []int64) []*strava.AthleteSummary {
+	athletes := make([]*strava.AthleteSummary, 0, len(athleteIds))
+	for _, id := range athleteIds {
+		if athlete := s.athletes[id]; athlete != nil {
+			athletes = append(athletes, &athlete.AthleteSummary)
+		}
+	}
+
+	start, end := page(r, len(athletes))
+	return athletes[start:end]
+}
+
+func containsId(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formValue returns the parameter and whether it was sent at all.
+func formValue(r *http.Request, key string) (string, bool) {
+	values, ok := r.Form[key]
+	if !ok || len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
@@ -0,0 +1,706 @@
+// Package stravatest provides an in-memory fake of the Strava API for testing code
+// that uses the strava package, without cassettes or network access.
+//
+//	server := stravatest.NewServer()
+//	defer server.Close()
+//
+//	server.AddAthlete(&strava.AthleteDetailed{...})
+//	client := server.Client()
+//	activity, err := strava.NewActivitiesService(client).Create(...).Do()
+package stravatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/strava/go.strava"
+)
+
+// BasePath is the path the API is served under, like https://www.strava.com/api/v3.
+const BasePath = "/api/v3"
+
+// Server stores athletes and their objects, and serves them like the Strava API.
+// Requests that create, update or delete objects change the store.
+//
+// Every athlete has an access token and requests are made as the athlete of their token,
+// requests without a valid token get a 401 response. The first athlete added is the
+// current athlete, whose token Client uses.
+//
+// The server keeps copies of the objects added, and the accessors return copies,
+// so they can be used while requests are being handled.
+type Server struct {
+	*httptest.Server
+
+	lock             sync.Mutex
+	nextId           int64
+	currentAthleteId int64
+
+	tokens map[string]int64 // athlete of each access token
+	codes  map[string]int64 // athlete of each unused authorization code
+
+	athletes     map[int64]*strava.AthleteDetailed
+	athleteZones map[int64]*strava.AthleteZones
+	activities   map[int64]*strava.ActivityDetailed
+	segments     map[int64]*strava.SegmentDetailed
+	efforts      map[int64]*strava.SegmentEffortDetailed
+	clubs        map[int64]*strava.ClubDetailed
+	clubMembers  map[int64][]int64
+	groupEvents  map[int64]*strava.GroupEvent
+	rsvps        map[int64][]int64 // athletes attending each group event
+	routes       map[int64]*strava.RouteDetailed
+	gear         map[string]*strava.GearDetailed
+	streams      map[streamParent]*strava.StreamSet
+	uploads      map[int64]*strava.UploadDetailed
+
+	// by activity id
+	comments map[int64][]*strava.CommentSummary
+	kudos    map[int64][]int64
+	laps     map[int64][]*strava.LapEffortSummary
+	zones    map[int64][]*strava.ZonesSummary
+
+	// activity created by each upload, set on the upload when it is checked
+	uploadActivities map[int64]int64
+
+	limitShort, limitLong   int
+	usageShort, usageLong   int
+	windowShort, windowLong time.Time // start of the 15 minutes and day the usage is for
+	faults                  []*Fault
+}
+
+// A Fault makes matching requests fail, eg. to test retries or error handling.
+type Fault struct {
+	Method string // matches any method if empty
+	Path   string // path after BasePath, eg. "/activities/123", matches any path if empty
+
+	StatusCode int    // defaults to 500
+	Body       string // defaults to a Strava error message
+
+	// Count is how many requests fail, 0 for every request until ClearFaults.
+	Count int
+}
+
+// streamParent is the object streams belong to, by its path, eg. {"activities", 123}.
+type streamParent struct {
+	path string
+	id   int64
+}
+
+func NewServer() *Server {
+	s := &Server{
+		nextId:           1000,
+		tokens:           make(map[string]int64),
+		codes:            make(map[string]int64),
+		athletes:         make(map[int64]*strava.AthleteDetailed),
+		athleteZones:     make(map[int64]*strava.AthleteZones),
+		activities:       make(map[int64]*strava.ActivityDetailed),
+		segments:         make(map[int64]*strava.SegmentDetailed),
+		efforts:          make(map[int64]*strava.SegmentEffortDetailed),
+		clubs:            make(map[int64]*strava.ClubDetailed),
+		clubMembers:      make(map[int64][]int64),
+		groupEvents:      make(map[int64]*strava.GroupEvent),
+		rsvps:            make(map[int64][]int64),
+		routes:           make(map[int64]*strava.RouteDetailed),
+		gear:             make(map[string]*strava.GearDetailed),
+		streams:          make(map[streamParent]*strava.StreamSet),
+		uploads:          make(map[int64]*strava.UploadDetailed),
+		comments:         make(map[int64][]*strava.CommentSummary),
+		kudos:            make(map[int64][]int64),
+		laps:             make(map[int64][]*strava.LapEffortSummary),
+		zones:            make(map[int64][]*strava.ZonesSummary),
+		uploadActivities: make(map[int64]int64),
+		limitShort:       600,
+		limitLong:        30000,
+	}
+
+	s.resetRateUsage(time.Now())
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Client returns a client that sends its requests to the server with the current athlete's token.
+func (s *Server) Client() *strava.Client {
+	s.lock.Lock()
+	token := s.token(s.currentAthleteId)
+	s.lock.Unlock()
+
+	return strava.NewClient(token, s.Server.Client()).SetBaseURL(s.URL + BasePath)
+}
+
+// Token returns the athlete's access token, empty if the athlete has deauthorized.
+func (s *Server) Token(athleteId int64) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.token(athleteId)
+}
+
+// AuthorizationCode returns a code that can be exchanged once for the athlete's token,
+// like the code Strava adds to the redirect after the athlete authorizes the application.
+// Use an OAuthAuthenticator with BaseURL set to server.URL + BasePath for the exchange.
+func (s *Server) AuthorizationCode(athleteId int64) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	code := fmt.Sprintf("code%d", s.id())
+	s.codes[code] = athleteId
+
+	return code
+}
+
+// AddAthlete stores the athlete and gives them a token, the first one added becomes the current athlete.
+func (s *Server) AddAthlete(athlete *strava.AthleteDetailed) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if athlete.Id == 0 {
+		athlete.Id = s.id()
+	}
+
+	if s.currentAthleteId == 0 {
+		s.currentAthleteId = athlete.Id
+	}
+
+	s.athletes[athlete.Id] = clone(athlete).(*strava.AthleteDetailed)
+	s.authorize(athlete.Id)
+}
+
+// SetCurrentAthlete changes the athlete whose token Client uses.
+func (s *Server) SetCurrentAthlete(athleteId int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.currentAthleteId = athleteId
+}
+
+// SetAthleteZones sets the heart rate and power zones returned for the athlete.
+func (s *Server) SetAthleteZones(athleteId int64, zones *strava.AthleteZones) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.athleteZones[athleteId] = clone(zones).(*strava.AthleteZones)
+}
+
+// AddActivity stores the activity, an id is assigned if it doesn't have one.
+// Activities without an athlete belong to the current athlete.
+func (s *Server) AddActivity(activity *strava.ActivityDetailed) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if activity.Id == 0 {
+		activity.Id = s.id()
+	}
+
+	if activity.Athlete.Id == 0 {
+		activity.Athlete.Id = s.currentAthleteId
+	}
+
+	s.activities[activity.Id] = clone(activity).(*strava.ActivityDetailed)
+}
+
+func (s *Server) AddSegment(segment *strava.SegmentDetailed) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if segment.Id == 0 {
+		segment.Id = s.id()
+	}
+
+	s.segments[segment.Id] = clone(segment).(*strava.SegmentDetailed)
+}
+
+// AddEffort stores an effort on a segment, used for leaderboards and KOMs.
+// Efforts without an athlete belong to the athlete of their activity, or the current athlete.
+func (s *Server) AddEffort(effort *strava.SegmentEffortDetailed) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if effort.Id == 0 {
+		effort.Id = s.id()
+	}
+
+	if effort.Athlete.Id == 0 {
+		effort.Athlete.Id = s.currentAthleteId
+		if a := s.activities[effort.Activity.Id]; a != nil {
+			effort.Athlete.Id = a.Athlete.Id
+		}
+	}
+
+	if segment := s.segments[effort.Segment.Id]; segment != nil && effort.Name == "" {
+		effort.Name = segment.Name
+	}
+
+	s.efforts[effort.Id] = clone(effort).(*strava.SegmentEffortDetailed)
+}
+
+// AddClub stores the club and its members.
+func (s *Server) AddClub(club *strava.ClubDetailed, memberIds ...int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if club.Id == 0 {
+		club.Id = s.id()
+	}
+
+	s.clubMembers[club.Id] = append(s.clubMembers[club.Id], memberIds...)
+	club.MemberCount = len(s.clubMembers[club.Id])
+
+	s.clubs[club.Id] = clone(club).(*strava.ClubDetailed)
+}
+
+// AddGroupEvent stores a club's event and the athletes attending it.
+func (s *Server) AddGroupEvent(event *strava.GroupEvent, athleteIds ...int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if event.Id == 0 {
+		event.Id = s.id()
+	}
+
+	s.groupEvents[event.Id] = clone(event).(*strava.GroupEvent)
+	s.rsvps[event.Id] = append(s.rsvps[event.Id], athleteIds...)
+}
+
+// AddRoute stores the route, routes without an athlete belong to the current athlete.
+func (s *Server) AddRoute(route *strava.RouteDetailed) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if route.Id == 0 {
+		route.Id = s.id()
+	}
+
+	if route.Athlete.Id == 0 {
+		route.Athlete.Id = s.currentAthleteId
+	}
+
+	s.routes[route.Id] = clone(route).(*strava.RouteDetailed)
+}
+
+// AddGear stores a bike or pair of shoes, an id like "g1001" is assigned if it doesn't have one.
+func (s *Server) AddGear(gear *strava.GearDetailed) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if gear.Id == "" {
+		gear.Id = fmt.Sprintf("g%d", s.id())
+	}
+
+	s.gear[gear.Id] = clone(gear).(*strava.GearDetailed)
+}
+
+// AddComment stores a comment on its ActivityId. Comments without an athlete are by the current athlete.
+func (s *Server) AddComment(comment *strava.CommentSummary) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if comment.Id == 0 {
+		comment.Id = s.id()
+	}
+
+	if comment.Athlete.Id == 0 {
+		comment.Athlete.Id = s.currentAthleteId
+		if a := s.athletes[comment.Athlete.Id]; a != nil {
+			comment.Athlete = a.AthleteSummary
+		}
+	}
+
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	}
+
+	s.comments[comment.ActivityId] = append(s.comments[comment.ActivityId], clone(comment).(*strava.CommentSummary))
+
+	if a := s.activities[comment.ActivityId]; a != nil {
+		a.CommentCount = len(s.comments[comment.ActivityId])
+	}
+}
+
+// AddKudos adds kudos from the athletes to the activity.
+func (s *Server) AddKudos(activityId int64, athleteIds ...int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, id := range athleteIds {
+		s.giveKudos(activityId, id)
+	}
+}
+
+func (s *Server) AddLaps(activityId int64, laps ...*strava.LapEffortSummary) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, lap := range laps {
+		s.laps[activityId] = append(s.laps[activityId], clone(lap).(*strava.LapEffortSummary))
+	}
+}
+
+// AddZones stores the heart rate or power zone distributions of an activity.
+func (s *Server) AddZones(activityId int64, zones ...*strava.ZonesSummary) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, z := range zones {
+		s.zones[activityId] = append(s.zones[activityId], clone(z).(*strava.ZonesSummary))
+	}
+}
+
+// AddStreams stores the streams of an activity. Streams without a Type are given
+// the type of their field, eg. StreamTypes.HeartRate for HeartRate.
+func (s *Server) AddStreams(activityId int64, streams *strava.StreamSet) {
+	s.addStreams(streamParent{"activities", activityId}, streams)
+}
+
+// AddSegmentStreams stores the streams of a segment, see AddStreams.
+func (s *Server) AddSegmentStreams(segmentId int64, streams *strava.StreamSet) {
+	s.addStreams(streamParent{"segments", segmentId}, streams)
+}
+
+// AddEffortStreams stores the streams of a segment effort, see AddStreams.
+func (s *Server) AddEffortStreams(effortId int64, streams *strava.StreamSet) {
+	s.addStreams(streamParent{"segment_efforts", effortId}, streams)
+}
+
+// AddRouteStreams stores the streams of a route, usually Location, Distance and Elevation, see AddStreams.
+func (s *Server) AddRouteStreams(routeId int64, streams *strava.StreamSet) {
+	s.addStreams(streamParent{"routes", routeId}, streams)
+}
+
+// Athlete returns a copy of the stored athlete, nil if there isn't one.
+func (s *Server) Athlete(athleteId int64) *strava.AthleteDetailed {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	athlete := s.athletes[athleteId]
+	if athlete == nil {
+		return nil
+	}
+
+	return clone(athlete).(*strava.AthleteDetailed)
+}
+
+// Activity returns a copy of the stored activity, nil if there isn't one or it was deleted.
+func (s *Server) Activity(activityId int64) *strava.ActivityDetailed {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	activity := s.activities[activityId]
+	if activity == nil {
+		return nil
+	}
+
+	return clone(activity).(*strava.ActivityDetailed)
+}
+
+func (s *Server) Segment(segmentId int64) *strava.SegmentDetailed {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	segment := s.segments[segmentId]
+	if segment == nil {
+		return nil
+	}
+
+	return clone(segment).(*strava.SegmentDetailed)
+}
+
+func (s *Server) Upload(uploadId int64) *strava.UploadDetailed {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	upload := s.uploads[uploadId]
+	if upload == nil {
+		return nil
+	}
+
+	return clone(upload).(*strava.UploadDetailed)
+}
+
+// SetRateLimits sets the 15 minute and daily limits, defaults to 600 and 30000.
+// Requests over either limit get a 429 response.
+func (s *Server) SetRateLimits(short, long int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.limitShort, s.limitLong = short, long
+}
+
+// SetRateUsage sets the number of requests made in the current 15 minutes and day, every request adds one.
+// Like Strava, the usage is reset every 15 minutes and at midnight UTC.
+func (s *Server) SetRateUsage(short, long int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.resetRateUsage(time.Now())
+	s.usageShort, s.usageLong = short, long
+}
+
+// InjectFault makes matching requests fail until its count runs out or ClearFaults.
+// Faults are checked in the order they were injected.
+func (s *Server) InjectFault(fault Fault) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if fault.StatusCode == 0 {
+		fault.StatusCode = http.StatusInternalServerError
+	}
+
+	s.faults = append(s.faults, &fault)
+}
+
+func (s *Server) ClearFaults() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.faults = nil
+}
+
+/*********************************************************/
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.resetRateUsage(time.Now())
+	s.usageShort++
+	s.usageLong++
+	w.Header().Set("X-Ratelimit-Limit", fmt.Sprintf("%d,%d", s.limitShort, s.limitLong))
+	w.Header().Set("X-Ratelimit-Usage", fmt.Sprintf("%d,%d", s.usageShort, s.usageLong))
+
+	if s.usageShort > s.limitShort || s.usageLong > s.limitLong {
+		writeError(w, http.StatusTooManyRequests, "Rate Limit Exceeded", "Application", "rate limit", "exceeded")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, BasePath)
+	if fault := s.fault(r.Method, path); fault != nil {
+		if fault.Body == "" {
+			writeError(w, fault.StatusCode, http.StatusText(fault.StatusCode), "Fault", "", "injected")
+			return
+		}
+
+		w.WriteHeader(fault.StatusCode)
+		w.Write([]byte(fault.Body))
+		return
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.ParseMultipartForm(32 << 20)
+	} else {
+		r.ParseForm()
+	}
+
+	// the token exchange is the only request without a token
+	if r.Method == "POST" && path == "/oauth/token" {
+		s.exchangeToken(w, r)
+		return
+	}
+
+	athleteId, ok := s.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "Authorization Error", "Athlete", "access_token", "invalid")
+		return
+	}
+
+	route(s, w, r, strings.Split(strings.Trim(path, "/"), "/"), athleteId)
+}
+
+// resetRateUsage clears the usage of earlier 15 minute windows and days.
+func (s *Server) resetRateUsage(now time.Time) {
+	now = now.UTC()
+
+	if short := now.Truncate(15 * time.Minute); !short.Equal(s.windowShort) {
+		s.windowShort, s.usageShort = short, 0
+	}
+
+	if long := now.Truncate(24 * time.Hour); !long.Equal(s.windowLong) {
+		s.windowLong, s.usageLong = long, 0
+	}
+}
+
+// fault returns the first matching fault, using up one of its count.
+func (s *Server) fault(method, path string) *Fault {
+	for i, f := range s.faults {
+		if (f.Method != "" && f.Method != method) || (f.Path != "" && f.Path != path) {
+			continue
+		}
+
+		if f.Count > 0 {
+			if f.Count--; f.Count == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+// token returns the athlete's current token, empty if they have deauthorized.
+func (s *Server) token(athleteId int64) string {
+	for token, id := range s.tokens {
+		if id == athleteId {
+			return token
+		}
+	}
+
+	return ""
+}
+
+// authorize returns the athlete's token, giving them a new one if they have deauthorized.
+func (s *Server) authorize(athleteId int64) string {
+	if token := s.token(athleteId); token != "" {
+		return token
+	}
+
+	token := fmt.Sprintf("token%d", s.id())
+	s.tokens[token] = athleteId
+
+	return token
+}
+
+func (s *Server) addStreams(parent streamParent, streams *strava.StreamSet) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// types are set on a copy, the set is encoded by them
+	typed := *streams
+
+	if streams.Location != nil {
+		location := *streams.Location
+		setStreamType(&location.Stream, strava.StreamTypes.Location)
+		typed.Location = &location
+	}
+
+	for t, stream := range map[strava.StreamType]**strava.IntegerStream{
+		strava.StreamTypes.Time:        &typed.Time,
+		strava.StreamTypes.HeartRate:   &typed.HeartRate,
+		strava.StreamTypes.Cadence:     &typed.Cadence,
+		strava.StreamTypes.Power:       &typed.Power,
+		strava.StreamTypes.Temperature: &typed.Temperature,
+	} {
+		if *stream != nil {
+			copied := **stream
+			setStreamType(&copied.Stream, t)
+			*stream = &copied
+		}
+	}
+
+	for t, stream := range map[strava.StreamType]**strava.DecimalStream{
+		strava.StreamTypes.Distance:  &typed.Distance,
+		strava.StreamTypes.Elevation: &typed.Elevation,
+		strava.StreamTypes.Speed:     &typed.Speed,
+		strava.StreamTypes.Grade:     &typed.Grade,
+	} {
+		if *stream != nil {
+			copied := **stream
+			setStreamType(&copied.Stream, t)
+			*stream = &copied
+		}
+	}
+
+	if streams.Moving != nil {
+		moving := *streams.Moving
+		setStreamType(&moving.Stream, strava.StreamTypes.Moving)
+		typed.Moving = &moving
+	}
+
+	s.streams[parent] = clone(&typed).(*strava.StreamSet)
+}
+
+func setStreamType(stream *strava.Stream, t strava.StreamType) {
+	if stream.Type == "" {
+		stream.Type = t
+	}
+}
+
+// clone returns a deep copy of the object v points to, so the server never shares
+// slices or pointers with the tests.
+func clone(v interface{}) interface{} {
+	copied := reflect.New(reflect.TypeOf(v).Elem()).Interface()
+
+	data, _ := json.Marshal(v)
+	json.Unmarshal(data, copied)
+
+	return copied
+}
+
+func (s *Server) id() int64 {
+	s.nextId++
+	return s.nextId
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error in the format of strava.Error.
+func writeError(w http.ResponseWriter, status int, message, resource, field, code string) {
+	writeJSON(w, status, &strava.Error{
+		Message: message,
+		Errors:  []*strava.ErrorDetailed{{Resource: resource, Field: field, Code: code}},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, resource string) {
+	writeError(w, http.StatusNotFound, "Record Not Found", resource, "id", "invalid")
+}
+
+// page returns the start and end of the requested page of n items,
+// with Strava's defaults of page 1 and 30 per page, at most 200.
+func page(r *http.Request, n int) (int, int) {
+	page, _ := strconv.Atoi(r.Form.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+
+	perPage, _ := strconv.Atoi(r.Form.Get("per_page"))
+	if perPage < 1 {
+		perPage = 30
+	}
+
+	if perPage > 200 {
+		perPage = 200
+	}
+
+	start := (page - 1) * perPage
+	if start > n {
+		start = n
+	}
+
+	end := start + perPage
+	if end > n {
+		end = n
+	}
+
+	return start, end
+}
+
+func parseId(s string) (int64, bool) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	return id, err == nil
+}
+
+func sortedIds(m map[int64]*strava.ActivityDetailed) []int64 {
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	return sortIds(ids)
+}
+
+func sortIds(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,878 @@
+package stravatest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/strava/go.strava"
+)
+
+func newTestServer() *Server {
+	server := NewServer()
+
+	athlete := &strava.AthleteDetailed{}
+	athlete.Id = 1
+	athlete.FirstName = "Marianne"
+	server.AddAthlete(athlete)
+
+	other := &strava.AthleteDetailed{}
+	other.Id = 2
+	other.FirstName = "Ferdinand"
+	server.AddAthlete(other)
+
+	return server
+}
+
+func TestServerCurrentAthlete(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	service := strava.NewCurrentAthleteService(server.Client())
+
+	athlete, err := service.Get().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.Id != 1 || athlete.FirstName != "Marianne" {
+		t.Errorf("athlete incorrect, got %v", athlete)
+	}
+
+	athlete, err = service.Update().City("Lyon").Weight(62.5).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.City != "Lyon" || athlete.Weight != 62.5 {
+		t.Errorf("athlete not updated, got %v", athlete)
+	}
+
+	if a := server.Athlete(1); a.City != "Lyon" {
+		t.Errorf("stored athlete not updated, got %v", a.City)
+	}
+
+	server.SetCurrentAthlete(2)
+	athlete, err = strava.NewCurrentAthleteService(server.Client()).Get().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.Id != 2 {
+		t.Errorf("current athlete not changed, got %d", athlete.Id)
+	}
+}
+
+func TestServerTokens(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	client := strava.NewClient(server.Token(2), http.DefaultClient).SetBaseURL(server.URL + BasePath)
+
+	athlete, err := strava.NewCurrentAthleteService(client).Get().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if athlete.Id != 2 {
+		t.Errorf("should be the athlete of the token, got %d", athlete.Id)
+	}
+
+	invalid := strava.NewClient("invalid", http.DefaultClient).SetBaseURL(server.URL + BasePath)
+
+	_, err = strava.NewCurrentAthleteService(invalid).Get().Do()
+	if e, ok := err.(strava.Error); !ok || e.Message != "Authorization Error" {
+		t.Errorf("should return authorization error, got %v", err)
+	}
+
+	if err := strava.NewOAuthService(client).Deauthorize().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if server.Token(2) != "" {
+		t.Errorf("token should be revoked, got %v", server.Token(2))
+	}
+
+	_, err = strava.NewCurrentAthleteService(client).Get().Do()
+	if _, ok := err.(strava.Error); !ok {
+		t.Errorf("revoked token should not work, got %v", err)
+	}
+}
+
+func TestServerOAuth(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	auth := strava.OAuthAuthenticator{BaseURL: server.URL + BasePath}
+	code := server.AuthorizationCode(2)
+
+	resp, err := auth.Authorize(code, nil)
+	if err != nil {
+		t.Fatalf("authorize error: %v", err)
+	}
+
+	if resp.AccessToken != server.Token(2) || resp.Athlete.Id != 2 {
+		t.Errorf("response incorrect, got %v", resp)
+	}
+
+	if _, err := auth.Authorize(code, nil); err != strava.OAuthInvalidCodeErr {
+		t.Errorf("code should only be used once, got %v", err)
+	}
+}
+
+func TestServerCopies(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	athlete := server.Athlete(1)
+	athlete.City = "Paris"
+
+	if server.Athlete(1).City != "" {
+		t.Error("should return a copy of the athlete")
+	}
+
+	activity := &strava.ActivityDetailed{}
+	activity.Map.Polyline = "abc"
+	activity.SegmentEfforts = []*strava.SegmentEffortSummary{{}}
+	activity.SegmentEfforts[0].Name = "Climb"
+	server.AddActivity(activity)
+
+	streams := &strava.StreamSet{Time: &strava.IntegerStream{Data: []int{0, 1, 2}}}
+	server.AddStreams(activity.Id, streams)
+
+	activity.Map.Polyline = "changed"
+	activity.SegmentEfforts[0].Name = "changed"
+	streams.Time.Data[2] = 100
+
+	if streams.Time.Type != "" {
+		t.Errorf("should not change the streams added, got type %v", streams.Time.Type)
+	}
+
+	client := server.Client()
+
+	a, err := strava.NewActivitiesService(client).Get(activity.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if a.Map.Polyline != "abc" || a.SegmentEfforts[0].Name != "Climb" {
+		t.Errorf("should not share nested data with the activity added, got %v", a)
+	}
+
+	set, err := strava.NewActivityStreamsService(client).Get(activity.Id, []strava.StreamType{strava.StreamTypes.Time}).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if set.Time == nil || set.Time.Data[2] != 2 {
+		t.Errorf("should not share stream data, got %v", set.Time)
+	}
+
+	stored := server.Activity(activity.Id)
+	stored.SegmentEfforts[0].Name = "changed"
+
+	if server.Activity(activity.Id).SegmentEfforts[0].Name != "Climb" {
+		t.Error("should return a deep copy of the activity")
+	}
+}
+
+func TestServerActivities(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	service := strava.NewActivitiesService(server.Client())
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	activity, err := service.Create("Morning Run", strava.ActivityTypes.Run, start, 1800).
+		Distance(5000).
+		SportType(strava.ActivityTypes.TrailRun).
+		Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if activity.Id == 0 || activity.Athlete.Id != 1 {
+		t.Errorf("activity not created for the current athlete, got %v", activity)
+	}
+
+	if activity.SportType != strava.ActivityTypes.TrailRun || activity.Distance != 5000 || !activity.StartDateLocal.Equal(start) {
+		t.Errorf("activity incorrect, got %v", activity)
+	}
+
+	activity, err = service.Update(activity.Id).Name("Evening Run").Private(true).Commute(true).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if activity.Name != "Evening Run" || !activity.Private || !activity.Commute {
+		t.Errorf("activity not updated, got %v", activity)
+	}
+
+	activity, err = service.Get(activity.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if activity.Name != "Evening Run" {
+		t.Errorf("update not stored, got %v", activity.Name)
+	}
+
+	if err := service.Delete(activity.Id).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if server.Activity(activity.Id) != nil {
+		t.Error("activity not deleted")
+	}
+
+	_, err = service.Get(activity.Id).Do()
+	if e, ok := err.(strava.Error); !ok || e.Message != "Record Not Found" {
+		t.Errorf("should return not found, got %v", err)
+	}
+
+	// errors
+	_, err = service.Create("", strava.ActivityTypes.Run, start, 1800).Do()
+	if e, ok := err.(strava.Error); !ok || e.Errors[0].Field != "name" {
+		t.Errorf("should return missing name, got %v", err)
+	}
+
+	other := &strava.ActivityDetailed{}
+	other.Athlete.Id = 2
+	server.AddActivity(other)
+
+	_, err = service.Update(other.Id).Name("mine").Do()
+	if _, ok := err.(strava.Error); !ok {
+		t.Errorf("should not update another athlete's activity, got %v", err)
+	}
+}
+
+func TestServerListActivities(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 45; i++ {
+		a := &strava.ActivityDetailed{}
+		a.StartDate = start.AddDate(0, 0, i)
+		server.AddActivity(a)
+	}
+
+	other := &strava.ActivityDetailed{}
+	other.Athlete.Id = 2
+	server.AddActivity(other)
+
+	service := strava.NewCurrentAthleteService(server.Client())
+
+	activities, err := service.ListActivities().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 30 {
+		t.Fatalf("should return default page size, got %d", len(activities))
+	}
+
+	if !activities[0].StartDate.Equal(start.AddDate(0, 0, 44)) {
+		t.Errorf("should be newest first, got %v", activities[0].StartDate)
+	}
+
+	activities, err = service.ListActivities().Page(2).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 15 {
+		t.Errorf("second page incorrect, got %d", len(activities))
+	}
+
+	activities, err = service.ListActivities().After(int(start.AddDate(0, 0, 40).Unix())).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 4 || !activities[0].StartDate.Equal(start.AddDate(0, 0, 41)) {
+		t.Errorf("after should be oldest first, got %d", len(activities))
+	}
+}
+
+func TestServerStreams(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	activity := &strava.ActivityDetailed{}
+	server.AddActivity(activity)
+
+	streams := &strava.StreamSet{
+		Time:      &strava.IntegerStream{Data: []int{0, 1, 2}},
+		HeartRate: &strava.IntegerStream{Data: []int{120, 121, 125}},
+		Distance:  &strava.DecimalStream{Data: []float64{0, 5, 10}},
+	}
+	server.AddStreams(activity.Id, streams)
+
+	service := strava.NewActivityStreamsService(server.Client())
+
+	set, err := service.Get(activity.Id, []strava.StreamType{strava.StreamTypes.Time, strava.StreamTypes.HeartRate, strava.StreamTypes.Power}).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if set.Time == nil || len(set.Time.Data) != 3 || set.HeartRate == nil || set.HeartRate.Data[2] != 125 {
+		t.Errorf("streams incorrect, got %v", set)
+	}
+
+	if set.Distance != nil || set.Power != nil {
+		t.Error("should only return requested streams the activity has")
+	}
+
+	_, err = service.Get(activity.Id+1, []strava.StreamType{strava.StreamTypes.Time}).Do()
+	if _, ok := err.(strava.Error); !ok {
+		t.Errorf("should return not found, got %v", err)
+	}
+}
+
+func TestServerActivityDetails(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	activity := &strava.ActivityDetailed{}
+	server.AddActivity(activity)
+
+	server.AddComment(&strava.CommentSummary{ActivityId: activity.Id, Text: "Nice"})
+	server.AddKudos(activity.Id, 2)
+	server.AddLaps(activity.Id, &strava.LapEffortSummary{})
+	server.AddZones(activity.Id, &strava.ZonesSummary{Type: "heartrate"})
+
+	client := server.Client()
+
+	comments := strava.NewActivityCommentsService(client, activity.Id)
+
+	comment, err := comments.Create("Thanks").Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if comment.Athlete.Id != 1 || comment.Text != "Thanks" {
+		t.Errorf("comment incorrect, got %v", comment)
+	}
+
+	list, err := comments.List().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(list) != 2 || list[0].Text != "Nice" {
+		t.Errorf("comments incorrect, got %v", list)
+	}
+
+	if err := comments.Delete(comment.Id).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if a := server.Activity(activity.Id); a.CommentCount != 1 {
+		t.Errorf("comment not deleted, got %d", a.CommentCount)
+	}
+
+	kudos := strava.NewActivityKudosService(client, activity.Id)
+	if err := kudos.Create().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	athletes, err := kudos.List().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(athletes) != 2 || athletes[0].Id != 2 {
+		t.Errorf("kudos incorrect, got %v", athletes)
+	}
+
+	a, err := strava.NewActivitiesService(client).Get(activity.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !a.HasKudoed || a.KudosCount != 2 {
+		t.Errorf("kudos not counted, got %v %d", a.HasKudoed, a.KudosCount)
+	}
+
+	if err := kudos.Delete().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if a := server.Activity(activity.Id); a.KudosCount != 1 {
+		t.Errorf("kudos not removed, got %d", a.KudosCount)
+	}
+
+	laps, err := strava.NewActivitiesService(client).ListLaps(activity.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(laps) != 1 {
+		t.Errorf("laps incorrect, got %d", len(laps))
+	}
+
+	zones, err := strava.NewActivitiesService(client).ListZones(activity.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(zones) != 1 || zones[0].Type != "heartrate" {
+		t.Errorf("zones incorrect, got %v", zones)
+	}
+}
+
+func TestServerEfforts(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	segment := &strava.SegmentDetailed{}
+	segment.Name = "Alpe d'Huez"
+	segment.ActivityType = strava.ActivityTypes.Ride
+	segment.ClimbCategory = strava.ClimbCategories.HorsCategorie
+	segment.StartLocation = strava.Location{45.05, 6.03}
+	segment.ElevationLow = 740
+	segment.ElevationHigh = 1850
+	server.AddSegment(segment)
+
+	start := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	times := map[int64][]int{1: {3000, 2900}, 2: {2800}}
+
+	for athleteId, elapsed := range times {
+		for i, e := range elapsed {
+			effort := &strava.SegmentEffortDetailed{}
+			effort.Segment.Id = segment.Id
+			effort.Athlete.Id = athleteId
+			effort.ElapsedTime = e
+			effort.StartDateLocal = start.AddDate(0, 0, i)
+			server.AddEffort(effort)
+		}
+	}
+
+	client := server.Client()
+	segments := strava.NewSegmentsService(client)
+
+	efforts, err := segments.ListEfforts(segment.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(efforts) != 2 || efforts[0].ElapsedTime != 3000 || efforts[0].Name != segment.Name {
+		t.Errorf("efforts incorrect, got %v", efforts)
+	}
+
+	efforts, err = segments.ListEfforts(segment.Id).DateRange(start.AddDate(0, 0, 1), start.AddDate(0, 0, 2)).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(efforts) != 1 || efforts[0].ElapsedTime != 2900 {
+		t.Errorf("efforts should be in the date range, got %v", efforts)
+	}
+
+	effort, err := strava.NewSegmentEffortsService(client).Get(efforts[0].Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if effort.ElapsedTime != 2900 {
+		t.Errorf("effort incorrect, got %v", effort)
+	}
+
+	leaderboard, err := segments.GetLeaderboard(segment.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if leaderboard.EntryCount != 2 || len(leaderboard.Entries) != 2 {
+		t.Fatalf("leaderboard should have each athlete's best effort, got %v", leaderboard)
+	}
+
+	if e := leaderboard.Entries[0]; e.AthleteId != 2 || e.Rank != 1 || e.AthleteName != "Ferdinand" {
+		t.Errorf("first entry incorrect, got %v", e)
+	}
+
+	if e := leaderboard.Entries[1]; e.AthleteId != 1 || e.Rank != 2 || e.ElapsedTime != 2900 {
+		t.Errorf("second entry incorrect, got %v", e)
+	}
+
+	leaderboard, err = segments.GetLeaderboard(segment.Id).PerPage(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(leaderboard.Entries) != 2 || leaderboard.Entries[1].AthleteId != 1 {
+		t.Errorf("should add context entries, got %v", leaderboard.Entries)
+	}
+
+	leaderboard, err = segments.GetLeaderboard(segment.Id).PerPage(1).ContextEntries(0).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(leaderboard.Entries) != 1 {
+		t.Errorf("should not add context entries, got %v", leaderboard.Entries)
+	}
+
+	koms, err := strava.NewAthletesService(client).ListKOMs(2).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(koms) != 1 || koms[0].ElapsedTime != 2800 {
+		t.Errorf("koms incorrect, got %v", koms)
+	}
+
+	found, err := segments.Explore(45, 6, 46, 7).ActivityType("riding").Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(found) != 1 || found[0].Id != segment.Id || found[0].ElevationDifference != 1110 {
+		t.Errorf("explore incorrect, got %v", found)
+	}
+
+	found, err = segments.Explore(45, 6, 46, 7).ActivityType("running").Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(found) != 0 {
+		t.Errorf("should filter by activity type, got %v", found)
+	}
+
+	server.AddSegmentStreams(segment.Id, &strava.StreamSet{Elevation: &strava.DecimalStream{Data: []float64{740, 1850}}})
+	server.AddEffortStreams(effort.Id, &strava.StreamSet{Time: &strava.IntegerStream{Data: []int{0, 2900}}})
+
+	set, err := strava.NewSegmentStreamsService(client).Get(segment.Id, []strava.StreamType{strava.StreamTypes.Elevation}).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if set.Elevation == nil || set.Elevation.Data[1] != 1850 {
+		t.Errorf("segment streams incorrect, got %v", set)
+	}
+
+	set, err = strava.NewSegmentEffortStreamsService(client).Get(effort.Id, []strava.StreamType{strava.StreamTypes.Time}).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if set.Time == nil || set.Time.Data[1] != 2900 {
+		t.Errorf("effort streams incorrect, got %v", set)
+	}
+}
+
+func TestServerSegmentsAndClubs(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	segment := &strava.SegmentDetailed{}
+	segment.Name = "Col de la Croix"
+	server.AddSegment(segment)
+
+	club := &strava.ClubDetailed{}
+	club.Name = "Velo Club"
+	server.AddClub(club, 2)
+
+	a := &strava.ActivityDetailed{}
+	a.Athlete.Id = 2
+	server.AddActivity(a)
+
+	client := server.Client()
+
+	s, err := strava.NewSegmentsService(client).Star(segment.Id, true).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !s.Starred || s.StarCount != 1 || !server.Segment(segment.Id).Starred {
+		t.Errorf("segment not starred, got %v", s)
+	}
+
+	clubs := strava.NewClubsService(client)
+
+	membership, err := clubs.Join(club.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !membership.Success || !membership.Active || membership.Membership != "member" {
+		t.Errorf("membership incorrect, got %v", membership)
+	}
+
+	members, err := clubs.ListMembers(club.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(members) != 2 {
+		t.Errorf("should have joined, got %d members", len(members))
+	}
+
+	activities, err := clubs.ListActivities(club.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(activities) != 1 || activities[0].Id != a.Id {
+		t.Errorf("club activities incorrect, got %v", activities)
+	}
+
+	if _, err := clubs.Leave(club.Id).Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	list, err := strava.NewCurrentAthleteService(client).ListClubs().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(list) != 0 {
+		t.Errorf("should have left, got %d clubs", len(list))
+	}
+}
+
+func TestServerUploads(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	service := strava.NewUploadsService(server.Client())
+
+	upload, err := service.Create(strava.FileDataTypes.GPX, "ride.gpx", strings.NewReader("<gpx/>")).
+		Name("Commute").
+		ActivityType(strava.ActivityTypes.EBikeRide).
+		Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if upload.Id == 0 || upload.ActivityId != 0 || upload.ExternalId != "ride.gpx" {
+		t.Errorf("upload should be processing, got %v", upload)
+	}
+
+	detailed, err := service.Get(upload.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if detailed.Status != "Your activity is ready." || detailed.ActivityId == 0 {
+		t.Fatalf("upload should be ready, got %v", detailed)
+	}
+
+	activity := server.Activity(detailed.ActivityId)
+	if activity == nil || activity.Name != "Commute" || activity.Type != strava.ActivityTypes.EBikeRide || activity.UploadId != upload.Id {
+		t.Errorf("uploaded activity incorrect, got %v", activity)
+	}
+}
+
+func TestServerRateLimits(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	server.SetRateLimits(10, 100)
+	server.SetRateUsage(8, 50)
+
+	service := strava.NewCurrentAthleteService(server.Client())
+
+	if _, err := service.Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if strava.RateLimiting.LimitShort != 10 || strava.RateLimiting.UsageShort != 9 || strava.RateLimiting.UsageLong != 51 {
+		t.Errorf("rate limits incorrect, got %v", &strava.RateLimiting)
+	}
+
+	service.Get().Do()
+	if !strava.RateLimiting.Exceeded() {
+		t.Error("should be exceeded")
+	}
+
+	_, err := service.Get().Do()
+	if e, ok := err.(strava.Error); !ok || e.Message != "Rate Limit Exceeded" {
+		t.Errorf("should return rate limit error, got %v", err)
+	}
+
+	// usage is reset in the next 15 minutes, and the next day
+	server.lock.Lock()
+	server.windowShort = server.windowShort.Add(-15 * time.Minute)
+	server.lock.Unlock()
+
+	if _, err := service.Get().Do(); err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if strava.RateLimiting.UsageShort != 1 || strava.RateLimiting.UsageLong != 54 {
+		t.Errorf("short term usage should be reset, got %v", &strava.RateLimiting)
+	}
+
+	server.lock.Lock()
+	server.windowLong = server.windowLong.Add(-24 * time.Hour)
+	server.lock.Unlock()
+
+	service.Get().Do()
+	if strava.RateLimiting.UsageShort != 2 || strava.RateLimiting.UsageLong != 1 {
+		t.Errorf("daily usage should be reset, got %v", &strava.RateLimiting)
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	service := strava.NewCurrentAthleteService(server.Client())
+
+	server.InjectFault(Fault{Method: "GET", Path: "/athlete", Count: 2})
+	server.InjectFault(Fault{Path: "/athlete/clubs", StatusCode: http.StatusUnauthorized, Body: `{"message":"Authorization Error"}`})
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.Get().Do(); err == nil || err.Error() != "server error" {
+			t.Errorf("request %d should fail, got %v", i, err)
+		}
+	}
+
+	if _, err := service.Get().Do(); err != nil {
+		t.Errorf("fault should be used up, got %v", err)
+	}
+
+	_, err := service.ListClubs().Do()
+	if e, ok := err.(strava.Error); !ok || e.Message != "Authorization Error" {
+		t.Errorf("should return fault body, got %v", err)
+	}
+
+	server.ClearFaults()
+	if _, err := service.ListClubs().Do(); err != nil {
+		t.Errorf("faults should be cleared, got %v", err)
+	}
+}
+
+func TestServerGroupEvents(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	club := &strava.ClubDetailed{}
+	server.AddClub(club, 1, 2)
+
+	next := &strava.GroupEvent{Title: "Saturday Ride", ClubId: club.Id, UpcomingOccurrences: []time.Time{time.Now().Add(24 * time.Hour)}}
+	server.AddGroupEvent(next, 2)
+
+	past := &strava.GroupEvent{Title: "Last Ride", ClubId: club.Id}
+	server.AddGroupEvent(past)
+
+	clubs := strava.NewClubsService(server.Client())
+
+	events, err := clubs.ListGroupEvents(club.Id).Upcoming(true).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(events) != 1 || events[0].Id != next.Id || events[0].Joined {
+		t.Errorf("upcoming events incorrect, got %v", events)
+	}
+
+	joined, err := clubs.JoinGroupEvent(next.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !joined {
+		t.Error("should have joined")
+	}
+
+	event, err := clubs.GetGroupEvent(next.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if !event.Joined {
+		t.Error("event should be joined")
+	}
+
+	athletes, err := clubs.ListGroupEventAthletes(next.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(athletes) != 2 {
+		t.Errorf("attendees incorrect, got %d", len(athletes))
+	}
+
+	if joined, err := clubs.LeaveGroupEvent(next.Id).Do(); err != nil || joined {
+		t.Errorf("should have left, got %v %v", joined, err)
+	}
+}
+
+func TestServerRoutesAndGear(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+
+	route := &strava.RouteDetailed{}
+	route.Name = "Loop"
+	server.AddRoute(route)
+
+	private := &strava.RouteDetailed{}
+	private.Athlete.Id = 2
+	private.Private = true
+	server.AddRoute(private)
+
+	server.AddRouteStreams(route.Id, &strava.StreamSet{Distance: &strava.DecimalStream{Data: []float64{0, 100}}})
+
+	gear := &strava.GearDetailed{}
+	gear.Name = "Bike"
+	server.AddGear(gear)
+
+	server.SetAthleteZones(1, &strava.AthleteZones{HeartRate: &strava.HeartRateZones{CustomZones: true}})
+
+	client := server.Client()
+	routes := strava.NewRoutesService(client)
+
+	r, err := routes.Get(route.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if r.Name != "Loop" || r.Athlete.Id != 1 {
+		t.Errorf("route incorrect, got %v", r)
+	}
+
+	if _, err := routes.Get(private.Id).Do(); err == nil {
+		t.Error("should not return another athlete's private route")
+	}
+
+	list, err := routes.ListByAthlete(1).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if len(list) != 1 || list[0].Id != route.Id {
+		t.Errorf("routes incorrect, got %v", list)
+	}
+
+	set, err := strava.NewRouteStreamsService(client).Get(route.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if set.Distance == nil || set.Distance.Data[1] != 100 {
+		t.Errorf("route streams incorrect, got %v", set)
+	}
+
+	g, err := strava.NewGearService(client).Get(gear.Id).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if g.Name != "Bike" {
+		t.Errorf("gear incorrect, got %v", g)
+	}
+
+	zones, err := strava.NewCurrentAthleteService(client).Zones().Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	if zones.HeartRate == nil || !zones.HeartRate.CustomZones {
+		t.Errorf("athlete zones incorrect, got %v", zones)
+	}
+}
@@ -0,0 +1,272 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SplitsByDistance divides the activity into splits of the given length in meters,
+// like SplitsMetric and SplitsStandard but for any distance. Splits begin and end
+// on stream samples so their distances are approximately, not exactly, the length.
+// The last split contains whatever remains.
+func (s *StreamSet) SplitsByDistance(meters float64) ([]*Split, error) {
+	if meters <= 0 {
+		return nil, errors.New("split distance must be positive")
+	}
+
+	boundaries, err := s.boundariesEvery(meters, s.cumulativeDistances)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.splits(boundaries), nil
+}
+
+// SplitsByDuration divides the activity into splits of the given elapsed time in seconds.
+func (s *StreamSet) SplitsByDuration(seconds int) ([]*Split, error) {
+	if seconds <= 0 {
+		return nil, errors.New("split duration must be positive")
+	}
+
+	boundaries, err := s.boundariesEvery(float64(seconds), s.elapsedTimes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.splits(boundaries), nil
+}
+
+// LapsByDistance generates auto-laps every given number of meters.
+func (s *StreamSet) LapsByDistance(meters float64) ([]*LapEffortSummary, error) {
+	if meters <= 0 {
+		return nil, errors.New("lap distance must be positive")
+	}
+
+	boundaries, err := s.boundariesEvery(meters, s.cumulativeDistances)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.laps(boundaries), nil
+}
+
+// LapsByDuration generates auto-laps every given number of elapsed seconds.
+func (s *StreamSet) LapsByDuration(seconds int) ([]*LapEffortSummary, error) {
+	if seconds <= 0 {
+		return nil, errors.New("lap duration must be positive")
+	}
+
+	boundaries, err := s.boundariesEvery(float64(seconds), s.elapsedTimes)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.laps(boundaries), nil
+}
+
+// LapsAtLocation generates a new lap every time the activity passes within
+// radius meters of the location. The lap is taken at the closest sample of each pass.
+// Starting within the radius does not count as a pass.
+// Requires the Location stream.
+func (s *StreamSet) LapsAtLocation(location Location, radius float64) ([]*LapEffortSummary, error) {
+	if s.Time == nil || s.Location == nil || len(s.Location.Data) != len(s.Time.Data) {
+		return nil, errors.New("time and location streams required")
+	}
+
+	samples := s.validTimeIndexes()
+	if len(samples) == 0 {
+		return nil, errors.New("no valid time samples")
+	}
+
+	boundaries := []int{samples[0]}
+
+	// a pass at the very start of the activity is not a lap
+	inside, starting := false, true
+	closest, closestDistance := -1, 0.0
+	for _, i := range samples {
+		l := s.Location.Data[i]
+		if Location(l).IsNullIsland() {
+			continue
+		}
+
+		d := location.DistanceTo(l)
+		if d <= radius {
+			if starting {
+				continue
+			}
+
+			if !inside || d < closestDistance {
+				closest, closestDistance = i, d
+			}
+			inside = true
+			continue
+		}
+		starting = false
+
+		if inside && closest > boundaries[len(boundaries)-1] {
+			boundaries = append(boundaries, closest)
+		}
+		inside = false
+	}
+
+	if inside && closest > boundaries[len(boundaries)-1] {
+		boundaries = append(boundaries, closest)
+	}
+
+	if last := samples[len(samples)-1]; last > boundaries[len(boundaries)-1] {
+		boundaries = append(boundaries, last)
+	}
+
+	return s.laps(boundaries), nil
+}
+
+/*********************************************************/
+
+// boundariesEvery returns the sample indexes where the cumulative values
+// cross each multiple of interval. The first and last valid samples are always included.
+func (s *StreamSet) boundariesEvery(interval float64, cumulative func([]int) []float64) ([]int, error) {
+	if s.Time == nil || len(s.Time.Data) == 0 {
+		return nil, errors.New("time stream required")
+	}
+
+	samples := s.validTimeIndexes()
+	if len(samples) == 0 {
+		return nil, errors.New("no valid time samples")
+	}
+
+	values := cumulative(samples)
+
+	boundaries := []int{samples[0]}
+	next := values[0] + interval
+	for j := 1; j < len(samples); j++ {
+		if values[j] >= next {
+			boundaries = append(boundaries, samples[j])
+			for next <= values[j] {
+				next += interval
+			}
+		}
+	}
+
+	if last := samples[len(samples)-1]; last > boundaries[len(boundaries)-1] {
+		boundaries = append(boundaries, last)
+	}
+
+	return boundaries, nil
+}
+
+// elapsedTimes returns the seconds from the start at each of the given samples.
+func (s *StreamSet) elapsedTimes(samples []int) []float64 {
+	times := make([]float64, len(samples))
+	for j, i := range samples {
+		times[j] = float64(s.Time.Data[i] - s.Time.Data[samples[0]])
+	}
+
+	return times
+}
+
+func (s *StreamSet) splits(boundaries []int) []*Split {
+	splits := make([]*Split, 0, len(boundaries))
+	for k := 1; k < len(boundaries); k++ {
+		start, end := boundaries[k-1], boundaries[k]
+		summary, _ := s.slice(start, end).Summary()
+
+		split := &Split{
+			Distance:    summary.Distance,
+			ElapsedTime: summary.ElapsedTime,
+			MovingTime:  summary.MovingTime,
+			Split:       k,
+		}
+
+		if s.Elevation != nil && s.Elevation.valid(start) && s.Elevation.valid(end) {
+			split.ElevationDifference = s.Elevation.Data[end] - s.Elevation.Data[start]
+		}
+
+		splits = append(splits, split)
+	}
+
+	return splits
+}
+
+func (s *StreamSet) laps(boundaries []int) []*LapEffortSummary {
+	laps := make([]*LapEffortSummary, 0, len(boundaries))
+	for k := 1; k < len(boundaries); k++ {
+		start, end := boundaries[k-1], boundaries[k]
+		summary, _ := s.slice(start, end).Summary()
+
+		lap := &LapEffortSummary{}
+		lap.Name = fmt.Sprintf("Lap %d", k)
+		lap.Distance = summary.Distance
+		lap.MovingTime = summary.MovingTime
+		lap.ElapsedTime = summary.ElapsedTime
+		lap.StartIndex = start
+		lap.EndIndex = end
+		lap.TotalElevationGain = summary.TotalElevationGain
+		lap.AverageSpeed = summary.AverageSpeed
+		lap.MaximunSpeed = summary.MaximunSpeed
+		lap.AverageCadence = summary.AverageCadence
+		lap.AveragePower = summary.AveragePower
+		lap.AverageHeartrate = summary.AverageHeartrate
+		lap.MaximumHeartrate = summary.MaximumHeartrate
+		lap.LapIndex = k
+
+		laps = append(laps, lap)
+	}
+
+	return laps
+}
+
+// slice returns the streams between start and end, inclusive.
+// The underlying data is shared with the original set.
+// Streams with a different length than Time are left out, as their samples don't line up.
+func (s *StreamSet) slice(start, end int) *StreamSet {
+	n := len(s.Time.Data)
+
+	set := &StreamSet{}
+	set.Time = s.Time.slice(start, end, n)
+
+	if s.Location != nil && len(s.Location.Data) == n {
+		set.Location = &LocationStream{s.Location.Stream, s.Location.Data[start : end+1]}
+	}
+
+	if s.Moving != nil && len(s.Moving.Data) == n {
+		set.Moving = &BooleanStream{s.Moving.Stream, s.Moving.Data[start : end+1]}
+	}
+
+	set.Distance = s.Distance.slice(start, end, n)
+	set.Elevation = s.Elevation.slice(start, end, n)
+	set.Speed = s.Speed.slice(start, end, n)
+	set.Grade = s.Grade.slice(start, end, n)
+	set.HeartRate = s.HeartRate.slice(start, end, n)
+	set.Cadence = s.Cadence.slice(start, end, n)
+	set.Power = s.Power.slice(start, end, n)
+	set.Temperature = s.Temperature.slice(start, end, n)
+
+	return set
+}
+
+// slice returns nil if the stream is nil or doesn't have n samples.
+func (s *IntegerStream) slice(start, end, n int) *IntegerStream {
+	if s == nil || len(s.Data) != n || (s.RawData != nil && len(s.RawData) != n) {
+		return nil
+	}
+
+	stream := &IntegerStream{Stream: s.Stream, Data: s.Data[start : end+1]}
+	if s.RawData != nil {
+		stream.RawData = s.RawData[start : end+1]
+	}
+
+	return stream
+}
+
+func (s *DecimalStream) slice(start, end, n int) *DecimalStream {
+	if s == nil || len(s.Data) != n || (s.RawData != nil && len(s.RawData) != n) {
+		return nil
+	}
+
+	stream := &DecimalStream{Stream: s.Stream, Data: s.Data[start : end+1]}
+	if s.RawData != nil {
+		stream.RawData = s.RawData[start : end+1]
+	}
+
+	return stream
+}
@@ -0,0 +1,166 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func newTestLapStreamSet() *StreamSet {
+	// a 100 meter out and back, repeated twice, at 5 m/s
+	set := &StreamSet{
+		Time:      &IntegerStream{Data: make([]int, 0)},
+		Distance:  &DecimalStream{Data: make([]float64, 0)},
+		Elevation: &DecimalStream{Data: make([]float64, 0)},
+		HeartRate: &IntegerStream{Data: make([]int, 0)},
+	}
+
+	for i := 0; i <= 80; i++ {
+		offset := i % 40
+		if offset > 20 {
+			offset = 40 - offset
+		}
+
+		set.Time.Data = append(set.Time.Data, i)
+		set.Distance.Data = append(set.Distance.Data, float64(5*i))
+		set.Elevation.Data = append(set.Elevation.Data, float64(offset))
+		set.HeartRate.Data = append(set.HeartRate.Data, 100+i)
+	}
+
+	return set
+}
+
+func TestStreamSetSplits(t *testing.T) {
+	set := newTestLapStreamSet()
+
+	splits, err := set.SplitsByDistance(150)
+	if err != nil {
+		t.Fatalf("splits error: %v", err)
+	}
+
+	if len(splits) != 3 {
+		t.Fatalf("incorrect number of splits, got %d", len(splits))
+	}
+
+	expected := []Split{
+		{Distance: 150, ElapsedTime: 30, MovingTime: 30, ElevationDifference: 10, Split: 1},
+		{Distance: 150, ElapsedTime: 30, MovingTime: 30, ElevationDifference: 10, Split: 2},
+		{Distance: 100, ElapsedTime: 20, MovingTime: 20, ElevationDifference: -20, Split: 3},
+	}
+
+	for i, s := range splits {
+		if *s != expected[i] {
+			t.Errorf("split %d incorrect, got %v", i, *s)
+		}
+	}
+
+	splits, err = set.SplitsByDuration(60)
+	if err != nil {
+		t.Fatalf("splits error: %v", err)
+	}
+
+	if len(splits) != 2 || splits[0].ElapsedTime != 60 || splits[1].ElapsedTime != 20 {
+		t.Errorf("duration splits incorrect, got %v", splits)
+	}
+
+	if _, err := set.SplitsByDistance(0); err == nil {
+		t.Error("should return error for invalid split distance")
+	}
+
+	if _, err := (&StreamSet{}).SplitsByDuration(60); err == nil {
+		t.Error("should return error if time stream missing")
+	}
+}
+
+func TestStreamSetLaps(t *testing.T) {
+	set := newTestLapStreamSet()
+
+	laps, err := set.LapsByDistance(200)
+	if err != nil {
+		t.Fatalf("laps error: %v", err)
+	}
+
+	if len(laps) != 2 {
+		t.Fatalf("incorrect number of laps, got %d", len(laps))
+	}
+
+	if laps[0].StartIndex != 0 || laps[0].EndIndex != 40 || laps[1].StartIndex != 40 || laps[1].EndIndex != 80 {
+		t.Errorf("lap indexes incorrect, got %v %v", laps[0].EffortSummary, laps[1].EffortSummary)
+	}
+
+	if laps[1].LapIndex != 2 || laps[1].Name != "Lap 2" {
+		t.Errorf("lap index incorrect, got %v", laps[1].LapIndex)
+	}
+
+	if laps[0].AverageSpeed != 5 || laps[0].TotalElevationGain != 20 {
+		t.Errorf("lap summary incorrect, got %v", laps[0])
+	}
+
+	if laps[1].MaximumHeartrate != 180 || math.Abs(laps[1].AverageHeartrate-160.5) > 1e-9 {
+		t.Errorf("lap heartrate incorrect, got %v %v", laps[1].AverageHeartrate, laps[1].MaximumHeartrate)
+	}
+
+	laps, err = set.LapsByDuration(30)
+	if err != nil {
+		t.Fatalf("laps error: %v", err)
+	}
+
+	if len(laps) != 3 {
+		t.Errorf("incorrect number of laps, got %d", len(laps))
+	}
+}
+
+func TestStreamSetLapsShortStreams(t *testing.T) {
+	set := newTestLapStreamSet()
+	set.HeartRate.Data = set.HeartRate.Data[:50]
+	set.Power = &IntegerStream{Data: []int{200, 210}}
+
+	laps, err := set.LapsByDistance(200)
+	if err != nil {
+		t.Fatalf("laps error: %v", err)
+	}
+
+	if len(laps) != 2 || laps[1].AverageSpeed != 5 {
+		t.Fatalf("laps incorrect, got %v", laps)
+	}
+
+	if laps[1].AverageHeartrate != 0 || laps[1].AveragePower != 0 {
+		t.Errorf("streams shorter than time should be skipped, got %v %v", laps[1].AverageHeartrate, laps[1].AveragePower)
+	}
+
+	if _, err := set.Summary(); err != nil {
+		t.Errorf("summary error: %v", err)
+	}
+}
+
+func TestStreamSetLapsAtLocation(t *testing.T) {
+	set := newTestLapStreamSet()
+	set.Location = &LocationStream{Data: make([][2]float64, len(set.Time.Data))}
+	for i := range set.Location.Data {
+		offset := i % 40
+		if offset > 20 {
+			offset = 40 - offset
+		}
+		set.Location.Data[i] = [2]float64{37.0, -122.0 + float64(offset)*0.0000565}
+	}
+
+	laps, err := set.LapsAtLocation(Location{37.0, -122.0}, 10)
+	if err != nil {
+		t.Fatalf("laps error: %v", err)
+	}
+
+	if len(laps) != 2 {
+		t.Fatalf("incorrect number of laps, got %d", len(laps))
+	}
+
+	if laps[0].EndIndex != 40 || laps[1].EndIndex != 80 {
+		t.Errorf("lap indexes incorrect, got %v %v", laps[0].EndIndex, laps[1].EndIndex)
+	}
+
+	if d := laps[0].Distance; math.Abs(d-200) > 1 {
+		t.Errorf("lap distance incorrect, got %v", d)
+	}
+
+	if _, err := (&StreamSet{}).LapsAtLocation(Location{37.0, -122.0}, 10); err == nil {
+		t.Error("should return error if location stream missing")
+	}
+}
@@ -0,0 +1,308 @@
+package strava
+
+import (
+	"errors"
+	"math"
+)
+
+// StreamSummary is an ActivitySummary derived locally from a StreamSet.
+// Only the fields that can be computed from stream data are set.
+// Maximum cadence and power are included as ActivitySummary has no place for them.
+type StreamSummary struct {
+	ActivitySummary
+	MaximumCadence float64 `json:"max_cadence"`
+	MaximumPower   float64 `json:"max_watts"`
+}
+
+// StreamSummaryOptions control how a StreamSet is summarized.
+type StreamSummaryOptions struct {
+	// Time is counted as moving only when the speed over the interval
+	// is at least this many meters per second. Not used if there is a Moving stream.
+	MovingSpeedThreshold float64
+
+	// Changes in elevation direction smaller than this many meters are
+	// treated as noise when computing TotalElevationGain.
+	ElevationHysteresis float64
+}
+
+// DefaultStreamSummaryOptions are used by Summary if no options are provided.
+var DefaultStreamSummaryOptions = StreamSummaryOptions{
+	MovingSpeedThreshold: 0.5,
+	ElevationHysteresis:  1.0,
+}
+
+// Summary computes the summary metrics of an activity from its streams.
+// The Time stream is required, all others are optional. Distance is computed
+// from the Location stream when available, falling back to the Distance stream.
+// Null Island, [0, 0], and nil samples are skipped.
+//
+// Moving time comes from the Moving stream, or else the Speed stream, or else the
+// speed between distances, and intervals covering too little distance are never moving.
+// Without any of them, eg. a trainer ride with only power, every sample is counted as moving.
+func (s *StreamSet) Summary(options ...StreamSummaryOptions) (*StreamSummary, error) {
+	opts := DefaultStreamSummaryOptions
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	if s.Time == nil || len(s.Time.Data) == 0 {
+		return nil, errors.New("time stream required")
+	}
+
+	summary := &StreamSummary{}
+
+	samples := s.validTimeIndexes()
+	if len(samples) == 0 {
+		return summary, nil
+	}
+
+	distances := s.cumulativeDistances(samples)
+	hasDistance := s.hasDistance()
+	summary.Distance = distances[len(distances)-1] - distances[0]
+	summary.ElapsedTime = s.Time.Data[samples[len(samples)-1]] - s.Time.Data[samples[0]]
+
+	var (
+		cadence, heartrate, power weightedAverage
+		joules                    float64
+	)
+
+	for j := 1; j < len(samples); j++ {
+		prev, i := samples[j-1], samples[j]
+
+		dt := float64(s.Time.Data[i] - s.Time.Data[prev])
+		if dt <= 0 {
+			continue
+		}
+
+		speed := (distances[j] - distances[j-1]) / dt
+		if s.Speed != nil && s.Speed.valid(i) {
+			summary.MaximunSpeed = math.Max(summary.MaximunSpeed, s.Speed.Data[i])
+		} else {
+			summary.MaximunSpeed = math.Max(summary.MaximunSpeed, speed)
+		}
+
+		if s.Power != nil && s.Power.valid(i) {
+			joules += float64(s.Power.Data[i]) * dt
+			summary.MaximumPower = math.Max(summary.MaximumPower, float64(s.Power.Data[i]))
+		}
+
+		if !s.moving(i, speed, hasDistance, opts.MovingSpeedThreshold) {
+			continue
+		}
+		summary.MovingTime += int(dt)
+
+		// power includes zeros from coasting, cadence and heartrate do not
+		if s.Power != nil && s.Power.valid(i) {
+			power.add(float64(s.Power.Data[i]), dt)
+		}
+
+		if s.Cadence != nil && s.Cadence.valid(i) && s.Cadence.Data[i] > 0 {
+			cadence.add(float64(s.Cadence.Data[i]), dt)
+			summary.MaximumCadence = math.Max(summary.MaximumCadence, float64(s.Cadence.Data[i]))
+		}
+
+		if s.HeartRate != nil && s.HeartRate.valid(i) && s.HeartRate.Data[i] > 0 {
+			heartrate.add(float64(s.HeartRate.Data[i]), dt)
+			summary.MaximumHeartrate = math.Max(summary.MaximumHeartrate, float64(s.HeartRate.Data[i]))
+		}
+	}
+
+	if summary.MovingTime > 0 {
+		summary.AverageSpeed = summary.Distance / float64(summary.MovingTime)
+	}
+
+	summary.AverageCadence = cadence.value()
+	summary.AverageHeartrate = heartrate.value()
+	summary.AveragePower = power.value()
+	summary.Kilojoules = joules / 1000.0
+
+	if s.Elevation != nil {
+		summary.TotalElevationGain = s.Elevation.gain(opts.ElevationHysteresis)
+	}
+
+	if s.Location != nil {
+		for _, l := range s.Location.Data {
+			if !Location(l).IsNullIsland() {
+				summary.StartLocation = Location(l)
+				break
+			}
+		}
+
+		for i := len(s.Location.Data) - 1; i >= 0; i-- {
+			if !Location(s.Location.Data[i]).IsNullIsland() {
+				summary.EndLocation = Location(s.Location.Data[i])
+				break
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// Discrepancies compares a server provided summary to one computed from streams.
+// It returns the json names of the fields whose relative difference is
+// greater than tolerance, eg. 0.05 for 5%. Fields that are zero in either
+// summary are not compared as they are most likely just unavailable.
+func (s *StreamSummary) Discrepancies(server *ActivitySummary, tolerance float64) []string {
+	fields := []struct {
+		name             string
+		computed, actual float64
+	}{
+		{"distance", s.Distance, server.Distance},
+		{"moving_time", float64(s.MovingTime), float64(server.MovingTime)},
+		{"elapsed_time", float64(s.ElapsedTime), float64(server.ElapsedTime)},
+		{"total_elevation_gain", s.TotalElevationGain, server.TotalElevationGain},
+		{"average_speed", s.AverageSpeed, server.AverageSpeed},
+		{"max_speed", s.MaximunSpeed, server.MaximunSpeed},
+		{"average_cadence", s.AverageCadence, server.AverageCadence},
+		{"average_watts", s.AveragePower, server.AveragePower},
+		{"kilojoules", s.Kilojoules, server.Kilojoules},
+		{"average_heartrate", s.AverageHeartrate, server.AverageHeartrate},
+		{"max_heartrate", s.MaximumHeartrate, server.MaximumHeartrate},
+	}
+
+	result := make([]string, 0)
+	for _, f := range fields {
+		if f.computed == 0 || f.actual == 0 {
+			continue
+		}
+
+		if math.Abs(f.computed-f.actual)/math.Abs(f.actual) > tolerance {
+			result = append(result, f.name)
+		}
+	}
+
+	return result
+}
+
+/*********************************************************/
+
+// validTimeIndexes returns the indexes of the non-nil time samples.
+func (s *StreamSet) validTimeIndexes() []int {
+	indexes := make([]int, 0, len(s.Time.Data))
+	for i := range s.Time.Data {
+		if s.Time.valid(i) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	return indexes
+}
+
+// moving is true if the athlete was moving in the interval ending at sample i, by the Moving
+// stream, or else the Speed stream, or else the speed from the distances. Without any of them
+// it is always true. Streams only sample the end of the interval, so with distances too an
+// interval covering too little distance for its length, like a pause in recording, is not moving.
+func (s *StreamSet) moving(i int, speed float64, hasDistance bool, threshold float64) bool {
+	if hasDistance && speed < threshold {
+		return false
+	}
+
+	switch {
+	case s.Moving != nil && len(s.Moving.Data) == len(s.Time.Data):
+		return s.Moving.Data[i]
+	case s.Speed != nil && len(s.Speed.Data) == len(s.Time.Data) && s.Speed.valid(i):
+		return s.Speed.Data[i] >= threshold
+	}
+
+	return true
+}
+
+// hasDistance is true if cumulativeDistances has a stream to compute distances from.
+func (s *StreamSet) hasDistance() bool {
+	return (s.Location != nil && len(s.Location.Data) == len(s.Time.Data)) ||
+		(s.Distance != nil && len(s.Distance.Data) == len(s.Time.Data))
+}
+
+// cumulativeDistances returns the distance from the start at each of the given samples.
+func (s *StreamSet) cumulativeDistances(samples []int) []float64 {
+	distances := make([]float64, len(samples))
+
+	if s.Location != nil && len(s.Location.Data) == len(s.Time.Data) {
+		var last Location
+		for j, i := range samples {
+			if j > 0 {
+				distances[j] = distances[j-1]
+			}
+
+			l := Location(s.Location.Data[i])
+			if l.IsNullIsland() {
+				continue
+			}
+
+			if !last.IsNullIsland() {
+				distances[j] += last.DistanceTo(l)
+			}
+			last = l
+		}
+
+		return distances
+	}
+
+	if s.Distance != nil && len(s.Distance.Data) == len(s.Time.Data) {
+		for j, i := range samples {
+			if s.Distance.valid(i) {
+				distances[j] = s.Distance.Data[i]
+			} else if j > 0 {
+				distances[j] = distances[j-1]
+			}
+		}
+	}
+
+	return distances
+}
+
+// gain returns the total positive change of the stream. A change in direction
+// is only recognized once it exceeds threshold, smaller wiggles are ignored.
+func (s *DecimalStream) gain(threshold float64) float64 {
+	var gain, reference float64
+	started, climbing, descending := false, false, false
+
+	for i, v := range s.Data {
+		if !s.valid(i) {
+			continue
+		}
+
+		if !started {
+			reference = v
+			started = true
+			continue
+		}
+
+		if v > reference && (climbing || v-reference >= threshold) {
+			gain += v - reference
+			reference = v
+			climbing, descending = true, false
+		} else if v < reference && (descending || reference-v >= threshold) {
+			reference = v
+			climbing, descending = false, true
+		}
+	}
+
+	return gain
+}
+
+func (s *IntegerStream) valid(i int) bool {
+	return i < len(s.Data) && (s.RawData == nil || (i < len(s.RawData) && s.RawData[i] != nil))
+}
+
+func (s *DecimalStream) valid(i int) bool {
+	return i < len(s.Data) && (s.RawData == nil || (i < len(s.RawData) && s.RawData[i] != nil))
+}
+
+type weightedAverage struct {
+	sum, weight float64
+}
+
+func (a *weightedAverage) add(value, weight float64) {
+	a.sum += value * weight
+	a.weight += weight
+}
+
+func (a *weightedAverage) value() float64 {
+	if a.weight == 0 {
+		return 0
+	}
+
+	return a.sum / a.weight
+}
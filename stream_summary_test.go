@@ -0,0 +1,163 @@
+package strava
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStreamSetSummary(t *testing.T) {
+	set := &StreamSet{
+		Time:      &IntegerStream{Data: []int{0, 10, 20, 30, 40}},
+		Distance:  &DecimalStream{Data: []float64{0, 50, 100, 100, 150}},
+		Elevation: &DecimalStream{Data: []float64{10, 11, 13, 12, 16}},
+		HeartRate: &IntegerStream{Data: []int{100, 120, 140, 0, 160}},
+		Power:     &IntegerStream{Data: []int{0, 200, 200, 0, 100}},
+	}
+
+	summary, err := set.Summary()
+	if err != nil {
+		t.Fatalf("summary error: %v", err)
+	}
+
+	if summary.Distance != 150 {
+		t.Errorf("distance incorrect, got %v", summary.Distance)
+	}
+
+	if summary.ElapsedTime != 40 {
+		t.Errorf("elapsed time incorrect, got %v", summary.ElapsedTime)
+	}
+
+	if summary.MovingTime != 30 {
+		t.Errorf("moving time incorrect, got %v", summary.MovingTime)
+	}
+
+	if summary.AverageSpeed != 5 {
+		t.Errorf("average speed incorrect, got %v", summary.AverageSpeed)
+	}
+
+	if summary.MaximunSpeed != 5 {
+		t.Errorf("max speed incorrect, got %v", summary.MaximunSpeed)
+	}
+
+	if summary.TotalElevationGain != 7 {
+		t.Errorf("elevation gain incorrect, got %v", summary.TotalElevationGain)
+	}
+
+	if summary.AverageHeartrate != 140 {
+		t.Errorf("average heartrate incorrect, got %v", summary.AverageHeartrate)
+	}
+
+	if summary.MaximumHeartrate != 160 {
+		t.Errorf("max heartrate incorrect, got %v", summary.MaximumHeartrate)
+	}
+
+	if v := summary.AveragePower; math.Abs(v-500.0/3) > 1e-9 {
+		t.Errorf("average power incorrect, got %v", v)
+	}
+
+	if summary.MaximumPower != 200 {
+		t.Errorf("max power incorrect, got %v", summary.MaximumPower)
+	}
+
+	if summary.Kilojoules != 5 {
+		t.Errorf("kilojoules incorrect, got %v", summary.Kilojoules)
+	}
+
+	// no time stream
+	if _, err := (&StreamSet{}).Summary(); err == nil {
+		t.Error("should return error if time stream missing")
+	}
+
+	// custom options
+	summary, _ = set.Summary(StreamSummaryOptions{MovingSpeedThreshold: 6})
+	if summary.MovingTime != 0 {
+		t.Errorf("moving time incorrect, got %v", summary.MovingTime)
+	}
+}
+
+func TestStreamSetSummaryMoving(t *testing.T) {
+	// trainer ride, no distance
+	set := &StreamSet{
+		Time:  &IntegerStream{Data: []int{0, 10, 20, 30}},
+		Power: &IntegerStream{Data: []int{150, 200, 250, 300}},
+	}
+
+	summary, err := set.Summary()
+	if err != nil {
+		t.Fatalf("summary error: %v", err)
+	}
+
+	if summary.MovingTime != 30 {
+		t.Errorf("trainer moving time incorrect, got %v", summary.MovingTime)
+	}
+
+	if v := summary.AveragePower; math.Abs(v-250) > 1e-9 {
+		t.Errorf("trainer average power incorrect, got %v", v)
+	}
+
+	// moving stream is used over distance
+	set.Distance = &DecimalStream{Data: []float64{0, 50, 100, 150}}
+	set.Moving = &BooleanStream{Data: []bool{false, true, false, true}}
+
+	summary, _ = set.Summary()
+	if summary.MovingTime != 20 {
+		t.Errorf("moving time incorrect, got %v", summary.MovingTime)
+	}
+
+	// then speed
+	set.Moving = nil
+	set.Distance = nil
+	set.Speed = &DecimalStream{Data: []float64{0, 5, 5, 0}}
+
+	summary, _ = set.Summary()
+	if summary.MovingTime != 20 {
+		t.Errorf("moving time from speed incorrect, got %v", summary.MovingTime)
+	}
+}
+
+func TestStreamSetSummaryFromCassette(t *testing.T) {
+	types := []StreamType{
+		StreamTypes.Time,
+		StreamTypes.Location,
+		StreamTypes.Distance,
+		StreamTypes.Elevation,
+		StreamTypes.Speed,
+		StreamTypes.HeartRate,
+		StreamTypes.Cadence,
+		StreamTypes.Power,
+	}
+
+	streams, err := NewActivityStreamsService(newCassetteClient(testToken, "activity_stream")).
+		Get(103221154, types).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	activity, err := NewActivitiesService(newCassetteClient(testToken, "activity_get")).
+		Get(103221154).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	summary, err := streams.Summary()
+	if err != nil {
+		t.Fatalf("summary error: %v", err)
+	}
+
+	if summary.StartLocation != (Location{38.546876, -121.817203}) {
+		t.Errorf("start location incorrect, got %v", summary.StartLocation)
+	}
+
+	if summary.EndLocation != (Location{38.560208, -121.777183}) {
+		t.Errorf("end location incorrect, got %v", summary.EndLocation)
+	}
+
+	if d := summary.Discrepancies(&activity.ActivitySummary, 0.05); len(d) != 0 {
+		t.Errorf("computed summary should match server summary, differs on %v", d)
+	}
+
+	activity.Distance *= 2
+	if d := summary.Discrepancies(&activity.ActivitySummary, 0.05); len(d) != 1 || d[0] != "distance" {
+		t.Errorf("should flag distance, got %v", d)
+	}
+}
@@ -95,6 +95,8 @@ type SegmentStreamsService streamsService
 
 type SegmentEffortStreamsService streamsService
 
+type RouteStreamsService streamsService
+
 type streamsService struct {
 	client     *Client
 	parentType int
@@ -104,7 +106,8 @@ var types = struct {
 	Activity      int
 	Segment       int
 	SegmentEffort int
-}{1, 2, 3}
+	Route         int
+}{1, 2, 3, 4}
 
 func NewActivityStreamsService(client *Client) *ActivityStreamsService {
 	return &ActivityStreamsService{client, types.Activity}
@@ -118,6 +121,10 @@ func NewSegmentEffortStreamsService(client *Client) *SegmentEffortStreamsService
 	return &SegmentEffortStreamsService{client, types.SegmentEffort}
 }
 
+func NewRouteStreamsService(client *Client) *RouteStreamsService {
+	return &RouteStreamsService{client, types.Route}
+}
+
 /*********************************************************/
 
 type ActivityStreamsGetCall struct {
@@ -132,6 +139,10 @@ type SegmentEffortStreamsGetCall struct {
 	streamsGetCall
 }
 
+type RouteStreamsGetCall struct {
+	streamsGetCall
+}
+
 type streamsGetCall struct {
 	service streamsService
 	id      int64
@@ -214,6 +225,20 @@ func (c *SegmentEffortStreamsGetCall) SeriesType(seriesType string) *SegmentEffo
 
 /*********************************************************/
 
+// Get returns the route's Location, Distance and Elevation streams.
+// Routes always return all their streams so none are requested.
+func (s *RouteStreamsService) Get(routeId int64) *RouteStreamsGetCall {
+	call := &RouteStreamsGetCall{}
+
+	call.service = streamsService(*s)
+	call.id = routeId
+	call.ops = make(map[string]interface{})
+
+	return call
+}
+
+/*********************************************************/
+
 func (c *streamsGetCall) Do() (*StreamSet, error) {
 	var source string
 	switch c.service.parentType {
@@ -223,22 +248,28 @@ func (c *streamsGetCall) Do() (*StreamSet, error) {
 		source = "segments"
 	case types.SegmentEffort:
 		source = "segment_efforts"
+	case types.Route:
+		source = "routes"
 	}
 
 	if source == "" {
 		return nil, errors.New("invalid stream parent type")
 	}
 
-	if len(c.types) == 0 {
-		return nil, errors.New("no streamtypes requested")
-	}
+	path := fmt.Sprintf("/%s/%d/streams", source, c.id)
+	if c.service.parentType != types.Route {
+		if len(c.types) == 0 {
+			return nil, errors.New("no streamtypes requested")
+		}
+
+		types := string(c.types[0])
+		for i := 1; i < len(c.types); i++ {
+			types += "," + string(c.types[i])
+		}
 
-	types := string(c.types[0])
-	for i := 1; i < len(c.types); i++ {
-		types += "," + string(c.types[i])
+		path += "/" + types
 	}
 
-	path := fmt.Sprintf("/%s/%d/streams/%s", source, c.id, types)
 	data, err := c.service.client.run("GET", path, c.ops)
 
 	if err != nil {
@@ -246,21 +277,35 @@ func (c *streamsGetCall) Do() (*StreamSet, error) {
 	}
 
 	var set StreamSet
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, err
+	}
 
-	streams := make([]interface{}, 0, 10)
-	json.Unmarshal(data, &streams)
+	return &set, nil
+}
+
+// streamJSON is a stream as Strava sends it.
+type streamJSON struct {
+	Type         StreamType    `json:"type"`
+	Data         []interface{} `json:"data"`
+	SeriesType   string        `json:"series_type,omitempty"` // not included for route streams
+	OriginalSize int           `json:"original_size,omitempty"`
+	Resolution   string        `json:"resolution,omitempty"`
+}
 
-	for _, stream := range streams {
-		m := stream.(map[string]interface{})
+// UnmarshalJSON decodes the list of streams Strava sends. Unknown stream types are skipped.
+func (set *StreamSet) UnmarshalJSON(data []byte) error {
+	var streams []*streamJSON
+	if err := json.Unmarshal(data, &streams); err != nil {
+		return err
+	}
 
-		var base interface{}
-		var s Stream
-		s.Type = StreamType(m["type"].(string))
-		s.SeriesType = m["series_type"].(string)
-		s.OriginalSize = int(m["original_size"].(float64))
-		s.Resolution = m["resolution"].(string)
+	for _, m := range streams {
+		s := Stream{m.Type, m.SeriesType, m.OriginalSize, m.Resolution}
 
-		switch StreamType(m["type"].(string)) {
+		var base filler
+		switch m.Type {
 		case StreamTypes.Time:
 			set.Time = &IntegerStream{s, nil, nil}
 			base = set.Time
@@ -304,14 +349,51 @@ func (c *streamsGetCall) Do() (*StreamSet, error) {
 		case StreamTypes.Grade:
 			set.Grade = &DecimalStream{s, nil, nil}
 			base = set.Grade
+
+		default:
+			continue
+		}
+
+		base.fill(m.Data)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the streams in the list format Strava sends, with nil values as null.
+func (set StreamSet) MarshalJSON() ([]byte, error) {
+	streams := make([]*streamJSON, 0, 11)
+
+	add := func(s Stream, data []interface{}) {
+		streams = append(streams, &streamJSON{s.Type, data, s.SeriesType, s.OriginalSize, s.Resolution})
+	}
+
+	if set.Location != nil {
+		add(set.Location.Stream, set.Location.values())
+	}
+
+	for _, s := range []*IntegerStream{set.Time, set.HeartRate, set.Cadence, set.Power, set.Temperature} {
+		if s != nil {
+			add(s.Stream, s.values())
 		}
+	}
 
-		f := base.(filler)
-		f.fill(m["data"].([]interface{}))
+	for _, s := range []*DecimalStream{set.Distance, set.Elevation, set.Speed, set.Grade} {
+		if s != nil {
+			add(s.Stream, s.values())
+		}
+	}
 
+	if set.Moving != nil {
+		data := make([]interface{}, len(set.Moving.Data))
+		for i, v := range set.Moving.Data {
+			data[i] = v
+		}
+
+		add(set.Moving.Stream, data)
 	}
 
-	return &set, nil
+	return json.Marshal(streams)
 }
 
 func (s *LocationStream) fill(data []interface{}) {
@@ -354,3 +436,36 @@ func (s *BooleanStream) fill(data []interface{}) {
 		}
 	}
 }
+
+func (s *LocationStream) values() []interface{} {
+	values := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		if v != [2]float64{0, 0} {
+			values[i] = v
+		}
+	}
+
+	return values
+}
+
+func (s *IntegerStream) values() []interface{} {
+	values := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		if s.valid(i) {
+			values[i] = v
+		}
+	}
+
+	return values
+}
+
+func (s *DecimalStream) values() []interface{} {
+	values := make([]interface{}, len(s.Data))
+	for i, v := range s.Data {
+		if s.valid(i) {
+			values[i] = v
+		}
+	}
+
+	return values
+}
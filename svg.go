@@ -0,0 +1,269 @@
+package strava
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+)
+
+// SVGStyle controls the size and look of the SVGs generated by RenderSVG.
+// Colors are any valid SVG color, eg. "#fc4c02" or "white".
+type SVGStyle struct {
+	Width   int
+	Height  int
+	Padding int
+
+	Background string
+
+	RouteColor string
+	RouteWidth float64
+
+	StartColor   string
+	EndColor     string
+	MarkerRadius float64
+
+	// Height of the elevation profile, taken from the bottom of the image.
+	// Set to 0 to not draw a profile.
+	ProfileHeight int
+	ProfileColor  string
+	ProfileFill   string
+
+	// Climbs in the profile are shaded with the color of the greatest MinimumGrade
+	// less than or equal to their average grade.
+	ClimbColors []SVGGradeColor
+}
+
+type SVGGradeColor struct {
+	MinimumGrade float64 // percent
+	Color        string
+}
+
+// DefaultSVGStyle is used by RenderSVG if no style is provided.
+var DefaultSVGStyle = SVGStyle{
+	Width:        600,
+	Height:       400,
+	Padding:      10,
+	Background:   "white",
+	RouteColor:   "#fc4c02",
+	RouteWidth:   3,
+	StartColor:   "#3bb143",
+	EndColor:     "#d62d20",
+	MarkerRadius: 5,
+
+	ProfileHeight: 120,
+	ProfileColor:  "#555555",
+	ProfileFill:   "#dddddd",
+	ClimbColors: []SVGGradeColor{
+		{3, "#ffd34e"},
+		{6, "#ff9a3c"},
+		{9, "#e8383d"},
+	},
+}
+
+// SVG renders the activity's route and, if streams with Elevation and Distance
+// are provided, an elevation profile. Streams may be nil.
+func (a *ActivitySummary) SVG(streams *StreamSet, style ...SVGStyle) string {
+	return RenderSVG(a.line(), streams, style...)
+}
+
+// SVG renders the segment's route and, if streams with Elevation and Distance
+// are provided, an elevation profile. Streams may be nil.
+func (s *SegmentDetailed) SVG(streams *StreamSet, style ...SVGStyle) string {
+	return RenderSVG(s.Map.Polyline.Decode(), streams, style...)
+}
+
+// RenderSVG returns a self-contained SVG image of the route through the points, eg.
+// a decoded Polyline, with start and end markers. If streams with Elevation and Distance
+// are provided an elevation profile with shaded climbs is drawn below the route.
+// The route is drawn using the web mercator projection, no map tiles are used.
+func RenderSVG(points [][2]float64, streams *StreamSet, style ...SVGStyle) string {
+	st := DefaultSVGStyle
+	if len(style) != 0 {
+		st = style[0]
+	}
+
+	drawProfile := st.ProfileHeight > 0 && streams != nil &&
+		streams.Elevation != nil && streams.Distance != nil
+
+	mapHeight := st.Height
+	if drawProfile {
+		mapHeight -= st.ProfileHeight
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		st.Width, st.Height, st.Width, st.Height)
+
+	if st.Background != "" {
+		fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`, svgEscape(st.Background))
+	}
+
+	svgRoute(&buf, points, st, svgArea{0, 0, float64(st.Width), float64(mapHeight)})
+
+	if drawProfile {
+		svgProfile(&buf, streams, st, svgArea{0, float64(mapHeight), float64(st.Width), float64(st.ProfileHeight)})
+	}
+
+	buf.WriteString("</svg>")
+	return buf.String()
+}
+
+/*********************************************************/
+
+// svgArea is the part of the svg to draw in
+type svgArea struct {
+	x, y, width, height float64
+}
+
+func svgRoute(buf *bytes.Buffer, points [][2]float64, st SVGStyle, area svgArea) {
+	line := make([][2]float64, 0, len(points))
+	for _, p := range points {
+		if !Location(p).IsNullIsland() {
+			x, y := mercator(p)
+			line = append(line, [2]float64{x, y})
+		}
+	}
+
+	if len(line) == 0 {
+		return
+	}
+
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, p := range line {
+		minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+
+	// fit keeping the aspect ratio, centered
+	padding := float64(st.Padding)
+	width, height := area.width-2*padding, area.height-2*padding
+	scale := math.Min(width/math.Max(maxX-minX, 1e-12), height/math.Max(maxY-minY, 1e-12))
+	offsetX := area.x + padding + (width-(maxX-minX)*scale)/2
+	offsetY := area.y + padding + (height-(maxY-minY)*scale)/2
+
+	project := func(p [2]float64) (float64, float64) {
+		return offsetX + (p[0]-minX)*scale, offsetY + (p[1]-minY)*scale
+	}
+
+	fmt.Fprintf(buf, `<polyline fill="none" stroke="%s" stroke-width="%s" stroke-linejoin="round" stroke-linecap="round" points="`,
+		svgEscape(st.RouteColor), svgNumber(st.RouteWidth))
+	for i, p := range line {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		x, y := project(p)
+		buf.WriteString(svgNumber(x) + "," + svgNumber(y))
+	}
+	buf.WriteString(`"/>`)
+
+	if st.MarkerRadius > 0 {
+		x, y := project(line[0])
+		fmt.Fprintf(buf, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`,
+			svgNumber(x), svgNumber(y), svgNumber(st.MarkerRadius), svgEscape(st.StartColor))
+
+		x, y = project(line[len(line)-1])
+		fmt.Fprintf(buf, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`,
+			svgNumber(x), svgNumber(y), svgNumber(st.MarkerRadius), svgEscape(st.EndColor))
+	}
+}
+
+func svgProfile(buf *bytes.Buffer, streams *StreamSet, st SVGStyle, area svgArea) {
+	indexes := make([]int, 0, len(streams.Elevation.Data))
+	for i := range streams.Elevation.Data {
+		if i < len(streams.Distance.Data) && streams.Elevation.valid(i) && streams.Distance.valid(i) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	if len(indexes) < 2 {
+		return
+	}
+
+	elevation, distance := streams.Elevation.Data, streams.Distance.Data
+
+	minElevation, maxElevation := math.Inf(1), math.Inf(-1)
+	for _, i := range indexes {
+		minElevation = math.Min(minElevation, elevation[i])
+		maxElevation = math.Max(maxElevation, elevation[i])
+	}
+
+	startDistance := distance[indexes[0]]
+	totalDistance := math.Max(distance[indexes[len(indexes)-1]]-startDistance, 1e-12)
+	elevationRange := math.Max(maxElevation-minElevation, 1)
+
+	padding := float64(st.Padding)
+	width, height := area.width-2*padding, area.height-2*padding
+	bottom := area.y + area.height - padding
+
+	project := func(i int) (float64, float64) {
+		x := area.x + padding + (distance[i]-startDistance)/totalDistance*width
+		y := bottom - (elevation[i]-minElevation)/elevationRange*height
+		return x, y
+	}
+
+	// no more than about one point per pixel
+	step := len(indexes)/int(math.Max(width, 1)) + 1
+
+	// fills the area under the profile between the start and end samples
+	fill := func(start, end int, color string) {
+		x0, _ := project(start)
+		x1, _ := project(end)
+
+		fmt.Fprintf(buf, `<path fill="%s" d="M%s,%s`, svgEscape(color), svgNumber(x0), svgNumber(bottom))
+
+		for k, i := range indexes {
+			if i < start || i > end || (k%step != 0 && i != end && i != start) {
+				continue
+			}
+
+			x, y := project(i)
+			buf.WriteString(" L" + svgNumber(x) + "," + svgNumber(y))
+		}
+
+		fmt.Fprintf(buf, ` L%s,%s Z"/>`, svgNumber(x1), svgNumber(bottom))
+	}
+
+	fill(indexes[0], indexes[len(indexes)-1], st.ProfileFill)
+
+	if len(st.ClimbColors) != 0 {
+		climbs, _ := streams.Climbs()
+		for _, c := range climbs {
+			color := ""
+			for _, gc := range st.ClimbColors {
+				if c.AverageGrade >= gc.MinimumGrade {
+					color = gc.Color
+				}
+			}
+
+			if color != "" {
+				fill(c.StartIndex, c.EndIndex, color)
+			}
+		}
+	}
+
+	// the outline on top of everything
+	fmt.Fprintf(buf, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="`, svgEscape(st.ProfileColor))
+	for k, i := range indexes {
+		if k%step != 0 && k != len(indexes)-1 {
+			continue
+		}
+
+		if k > 0 {
+			buf.WriteByte(' ')
+		}
+		x, y := project(i)
+		buf.WriteString(svgNumber(x) + "," + svgNumber(y))
+	}
+	buf.WriteString(`"/>`)
+}
+
+func svgNumber(v float64) string {
+	return fmt.Sprintf("%.1f", v)
+}
+
+func svgEscape(s string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
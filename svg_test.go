@@ -0,0 +1,82 @@
+package strava
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderSVG(t *testing.T) {
+	client := newCassetteClient(testToken, "segment_get")
+	segment, err := NewSegmentsService(client).Get(229781).Do()
+	if err != nil {
+		t.Fatalf("service error: %v", err)
+	}
+
+	// route only
+	svg := segment.SVG(nil)
+	if err := xml.Unmarshal([]byte(svg), new(interface{})); err != nil {
+		t.Errorf("svg should be valid xml: %v", err)
+	}
+
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="600" height="400"`) {
+		t.Errorf("svg header incorrect, got %v", svg[:100])
+	}
+
+	if c := strings.Count(svg, "<polyline"); c != 1 {
+		t.Errorf("route only should have 1 polyline, got %d", c)
+	}
+
+	if c := strings.Count(svg, "<circle"); c != 2 {
+		t.Errorf("should have start and end markers, got %d", c)
+	}
+
+	// with a profile containing one climb
+	streams := &StreamSet{
+		Distance:  &DecimalStream{Data: []float64{0, 100, 200, 300, 400, 500, 600}},
+		Elevation: &DecimalStream{Data: []float64{10, 10, 20, 30, 40, 40, 40}},
+	}
+
+	style := DefaultSVGStyle
+	style.Width = 300
+	style.Height = 200
+	style.ClimbColors = []SVGGradeColor{{3, "yellow"}, {9, "red"}}
+
+	svg = segment.SVG(streams, style)
+	if err := xml.Unmarshal([]byte(svg), new(interface{})); err != nil {
+		t.Errorf("svg should be valid xml: %v", err)
+	}
+
+	if !strings.Contains(svg, `width="300" height="200"`) {
+		t.Error("svg size should come from style")
+	}
+
+	if c := strings.Count(svg, "<polyline"); c != 2 {
+		t.Errorf("should have route and profile polylines, got %d", c)
+	}
+
+	if c := strings.Count(svg, "<path"); c != 2 {
+		t.Errorf("should have profile and climb fills, got %d", c)
+	}
+
+	if !strings.Contains(svg, `fill="red"`) || strings.Contains(svg, `fill="yellow"`) {
+		t.Error("climb should be shaded by grade")
+	}
+
+	// profile is drawn at the bottom, route above it
+	route := svg[strings.Index(svg, `points="`)+8:]
+	route = route[:strings.Index(route, `"`)]
+	for _, p := range strings.Fields(route) {
+		var x, y float64
+		fmt.Sscanf(p, "%f,%f", &x, &y)
+		if y > 80 {
+			t.Errorf("route should not overlap the profile, got %v", p)
+		}
+	}
+
+	activity := &ActivitySummary{}
+	if svg := activity.SVG(nil); strings.Contains(svg, "<polyline") {
+		t.Error("activity with no route should not draw one")
+	}
+}
@@ -0,0 +1,86 @@
+package strava
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeZoneLocation resolves TimeZone, eg. "(GMT-08:00) America/Los_Angeles", into a location.
+// If the zone database does not have the zone a fixed zone with the GMT offset is returned,
+// which is only correct outside of daylight saving time. If TimeZone can't be parsed the offset
+// between StartDateLocal and StartDate is used instead.
+func (a *ActivitySummary) TimeZoneLocation() (*time.Location, error) {
+	offset, name, err := parseTimeZone(a.TimeZone)
+	if err == nil {
+		if location, err := time.LoadLocation(name); err == nil {
+			return location, nil
+		}
+
+		return time.FixedZone(name, offset), nil
+	}
+
+	if a.StartDate.IsZero() || a.StartDateLocal.IsZero() {
+		return nil, err
+	}
+
+	return time.FixedZone("", int(a.StartDateLocal.Sub(a.StartDate)/time.Second)), nil
+}
+
+// LocalStartDate returns StartDate in the activity's time zone. StartDateLocal has the
+// same clock time but is parsed as UTC, so comparisons with other times are off by the offset.
+func (a *ActivitySummary) LocalStartDate() (time.Time, error) {
+	location, err := a.TimeZoneLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return a.StartDate.In(location), nil
+}
+
+// LocalTimes returns the time of each sample of the Time stream in the activity's time zone.
+// Nil samples are the zero time.
+func (s *StreamSet) LocalTimes(activity *ActivitySummary) ([]time.Time, error) {
+	if s.Time == nil {
+		return nil, errors.New("time stream required")
+	}
+
+	start, err := activity.LocalStartDate()
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, len(s.Time.Data))
+	for i, t := range s.Time.Data {
+		if s.Time.valid(i) {
+			times[i] = start.Add(time.Duration(t) * time.Second)
+		}
+	}
+
+	return times, nil
+}
+
+/*********************************************************/
+
+// parseTimeZone returns the offset in seconds and the zone name of eg. "(GMT-08:00) America/Los_Angeles".
+func parseTimeZone(zone string) (int, string, error) {
+	var sign byte
+	var hours, minutes int
+	var name string
+
+	if !strings.HasPrefix(zone, "(GMT") {
+		return 0, "", fmt.Errorf("time zone %q not recognized", zone)
+	}
+
+	if _, err := fmt.Sscanf(zone, "(GMT%c%d:%d) %s", &sign, &hours, &minutes, &name); err != nil || (sign != '+' && sign != '-') {
+		return 0, "", fmt.Errorf("time zone %q not recognized", zone)
+	}
+
+	offset := hours*3600 + minutes*60
+	if sign == '-' {
+		offset = -offset
+	}
+
+	return offset, name, nil
+}
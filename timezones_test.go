@@ -0,0 +1,86 @@
+package strava
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // the zones don't depend on the system's time zone database
+)
+
+func TestActivitySummaryTimeZoneLocation(t *testing.T) {
+	activity := &ActivitySummary{TimeZone: "(GMT-08:00) America/Los_Angeles"}
+	activity.StartDate, _ = time.Parse(timeFormat, "2010-12-15T18:04:29Z")
+	activity.StartDateLocal, _ = time.Parse(timeFormat, "2010-12-15T10:04:29Z")
+
+	location, err := activity.TimeZoneLocation()
+	if err != nil {
+		t.Fatalf("time zone error: %v", err)
+	}
+
+	if location.String() != "America/Los_Angeles" {
+		t.Errorf("location incorrect, got %v", location)
+	}
+
+	start, err := activity.LocalStartDate()
+	if err != nil {
+		t.Fatalf("local start date error: %v", err)
+	}
+
+	if !start.Equal(activity.StartDate) || start.Hour() != 10 || start.Day() != 15 {
+		t.Errorf("local start date incorrect, got %v", start)
+	}
+
+	if _, offset := start.Zone(); offset != -8*3600 {
+		t.Errorf("offset incorrect, got %d", offset)
+	}
+
+	// zones not in the database use the offset
+	activity.TimeZone = "(GMT+05:30) Not/AZone"
+	start, _ = activity.LocalStartDate()
+	if _, offset := start.Zone(); offset != 5*3600+1800 {
+		t.Errorf("offset incorrect, got %d", offset)
+	}
+
+	// unrecognized zones use the local start date
+	activity.TimeZone = ""
+	start, _ = activity.LocalStartDate()
+	if _, offset := start.Zone(); offset != -8*3600 || start.Hour() != 10 {
+		t.Errorf("offset incorrect, got %d", offset)
+	}
+
+	// errors
+	if _, err := (&ActivitySummary{TimeZone: "Pacific"}).TimeZoneLocation(); err == nil {
+		t.Error("should return an error")
+	}
+}
+
+func TestStreamSetLocalTimes(t *testing.T) {
+	activity := &ActivitySummary{TimeZone: "(GMT+09:00) Asia/Tokyo"}
+	activity.StartDate, _ = time.Parse(timeFormat, "2014-03-01T14:59:50Z")
+
+	set := &StreamSet{Time: &IntegerStream{
+		Data:    []int{0, 0, 20},
+		RawData: []*int{new(int), nil, new(int)},
+	}}
+
+	times, err := set.LocalTimes(activity)
+	if err != nil {
+		t.Fatalf("local times error: %v", err)
+	}
+
+	if times[0].Day() != 1 || times[0].Hour() != 23 {
+		t.Errorf("first time incorrect, got %v", times[0])
+	}
+
+	if !times[1].IsZero() {
+		t.Errorf("nil sample should be the zero time, got %v", times[1])
+	}
+
+	if times[2].Day() != 2 || times[2].Hour() != 0 || times[2].Second() != 10 {
+		t.Errorf("last time should be the next day, got %v", times[2])
+	}
+
+	// errors
+	if _, err := (&StreamSet{}).LocalTimes(activity); err == nil {
+		t.Error("should require the time stream")
+	}
+}
@@ -0,0 +1,215 @@
+package strava
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// TrainingStress scores the stress of an activity, eg. SufferScoreStress or PowerStress.
+// Scores from streams, see StreamSet.TrainingStressScore, can be used by looking them up by activity id.
+type TrainingStress func(activity *ActivitySummary) float64
+
+// A TrainingLoadDay is one day of a performance management chart.
+type TrainingLoadDay struct {
+	// Date is midnight UTC of the local day, in the same form as ActivitySummary.StartDateLocal.
+	Date time.Time
+
+	// Stress is the total of the day's activities, 0 for rest days.
+	Stress float64
+
+	// ChronicLoad is fitness (CTL), AcuteLoad is fatigue (ATL), after the day's activities.
+	ChronicLoad float64
+	AcuteLoad   float64
+
+	// Balance is form (TSB), the previous day's fitness minus fatigue.
+	Balance float64
+}
+
+type TrainingLoadOptions struct {
+	// Days over which training is averaged, defaults to 42 for fitness and 7 for fatigue.
+	ChronicTimeConstant float64
+	AcuteTimeConstant   float64
+
+	// Stress scores each activity, defaults to SufferScoreStress.
+	Stress TrainingStress
+
+	// Loads before the first activity, eg. from a previous computation.
+	InitialChronicLoad float64
+	InitialAcuteLoad   float64
+
+	// The series is filled with rest days until End if it is after the last activity, eg. time.Now()
+	// so fitness and fatigue decay to today. Compared with the local start dates of the activities.
+	End time.Time
+}
+
+// DefaultTrainingLoadOptions are used by TrainingLoad if no options are provided.
+var DefaultTrainingLoadOptions = TrainingLoadOptions{
+	ChronicTimeConstant: 42,
+	AcuteTimeConstant:   7,
+	Stress:              SufferScoreStress,
+}
+
+// TrainingLoad computes fitness, fatigue and form for every day from the first activity
+// to the last, or options End, including rest days. The activities do not need to be in order,
+// they are grouped into days by StartDateLocal. Each day's loads move 1/time constant
+// of the way towards the day's stress, as in the usual performance management chart.
+func TrainingLoad(activities []*ActivitySummary, options ...TrainingLoadOptions) []*TrainingLoadDay {
+	opts := DefaultTrainingLoadOptions
+	if len(options) != 0 {
+		opts = options[0]
+	}
+
+	if opts.ChronicTimeConstant <= 0 {
+		opts.ChronicTimeConstant = DefaultTrainingLoadOptions.ChronicTimeConstant
+	}
+
+	if opts.AcuteTimeConstant <= 0 {
+		opts.AcuteTimeConstant = DefaultTrainingLoadOptions.AcuteTimeConstant
+	}
+
+	if opts.Stress == nil {
+		opts.Stress = DefaultTrainingLoadOptions.Stress
+	}
+
+	days := make([]*TrainingLoadDay, 0)
+	if len(activities) == 0 {
+		return days
+	}
+
+	stress := make(map[time.Time]float64)
+	dates := make([]time.Time, 0, len(activities))
+	for _, a := range activities {
+		date := localDate(a.StartDateLocal)
+		stress[date] += opts.Stress(a)
+		dates = append(dates, date)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	first, last := dates[0], dates[len(dates)-1]
+	if end := localDate(opts.End); !opts.End.IsZero() && end.After(last) {
+		last = end
+	}
+
+	ctl, atl := opts.InitialChronicLoad, opts.InitialAcuteLoad
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		day := &TrainingLoadDay{
+			Date:    date,
+			Stress:  stress[date],
+			Balance: ctl - atl,
+		}
+
+		ctl += (day.Stress - ctl) / opts.ChronicTimeConstant
+		atl += (day.Stress - atl) / opts.AcuteTimeConstant
+		day.ChronicLoad, day.AcuteLoad = ctl, atl
+
+		days = append(days, day)
+	}
+
+	return days
+}
+
+// SufferScoreStress uses Strava's relative effort, 0 for activities without heart rate.
+func SufferScoreStress(activity *ActivitySummary) float64 {
+	return activity.SufferScore
+}
+
+// PowerStress estimates the training stress score of rides with power for an athlete's FTP
+// in watts, using WeightedAveragePower as normalized power. If that isn't available
+// the average power from Kilojoules over the moving time is used. 0 for activities without power.
+func PowerStress(ftp int) TrainingStress {
+	return func(activity *ActivitySummary) float64 {
+		if ftp <= 0 || activity.MovingTime <= 0 {
+			return 0
+		}
+
+		power := float64(activity.WeightedAveragePower)
+		if power == 0 {
+			power = activity.Kilojoules * 1000 / float64(activity.MovingTime)
+		}
+
+		return trainingStressScore(float64(activity.MovingTime), power, float64(ftp))
+	}
+}
+
+// the longest time between samples, in seconds, that power is held over,
+// longer gaps are pauses in recording and are skipped
+const maximumPowerGap = 10
+
+// TrainingStressScore computes the training stress score of an activity from its
+// Time and Power streams for an athlete's FTP in watts. Normalized power is taken
+// from 30 second rolling averages of the power held between samples. Gaps between
+// samples longer than 10 seconds, or where the Moving stream is false, are skipped
+// so stops don't count as riding at the power after them.
+func (s *StreamSet) TrainingStressScore(ftp int) (float64, error) {
+	if ftp <= 0 {
+		return 0, errors.New("ftp must be positive")
+	}
+
+	if s.Power == nil || s.Time == nil || len(s.Power.Data) != len(s.Time.Data) {
+		return 0, errors.New("time and power streams required")
+	}
+
+	// power for each second
+	seconds := make([]float64, 0)
+	samples := s.validTimeIndexes()
+	for j := 1; j < len(samples); j++ {
+		prev, i := samples[j-1], samples[j]
+
+		if s.Time.Data[i]-s.Time.Data[prev] > maximumPowerGap {
+			continue
+		}
+
+		if s.Moving != nil && len(s.Moving.Data) == len(s.Time.Data) && !s.Moving.Data[i] {
+			continue
+		}
+
+		power := 0.0
+		if s.Power.valid(i) {
+			power = float64(s.Power.Data[i])
+		}
+
+		for t := s.Time.Data[prev]; t < s.Time.Data[i]; t++ {
+			seconds = append(seconds, power)
+		}
+	}
+
+	if len(seconds) == 0 {
+		return 0, nil
+	}
+
+	const window = 30
+
+	var sum, fourth float64
+	count := 0
+	for i, p := range seconds {
+		sum += p
+		if i >= window {
+			sum -= seconds[i-window]
+		}
+
+		if i >= window-1 || len(seconds) < window && i == len(seconds)-1 {
+			average := sum / math.Min(float64(i+1), window)
+			fourth += math.Pow(average, 4)
+			count++
+		}
+	}
+
+	normalized := math.Pow(fourth/float64(count), 0.25)
+	return trainingStressScore(float64(len(seconds)), normalized, float64(ftp)), nil
+}
+
+/*********************************************************/
+
+// trainingStressScore is 100 for an hour at FTP.
+func trainingStressScore(seconds, normalizedPower, ftp float64) float64 {
+	intensity := normalizedPower / ftp
+	return seconds * normalizedPower * intensity / (ftp * 3600) * 100
+}
+
+// localDate returns midnight of the day of a local time stored as UTC.
+func localDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
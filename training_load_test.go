@@ -0,0 +1,161 @@
+package strava
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTrainingLoad(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	activities := []*ActivitySummary{
+		{StartDateLocal: start.Add(50 * time.Hour), SufferScore: 40}, // out of order
+		{StartDateLocal: start.Add(8 * time.Hour), SufferScore: 70},
+		{StartDateLocal: start.Add(18 * time.Hour), SufferScore: 30},
+	}
+
+	days := TrainingLoad(activities)
+	if len(days) != 3 {
+		t.Fatalf("should fill rest days, got %d days", len(days))
+	}
+
+	if !days[0].Date.Equal(start) || !days[2].Date.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("dates incorrect, got %v to %v", days[0].Date, days[2].Date)
+	}
+
+	if days[0].Stress != 100 || days[1].Stress != 0 || days[2].Stress != 40 {
+		t.Errorf("stress incorrect, got %v %v %v", days[0].Stress, days[1].Stress, days[2].Stress)
+	}
+
+	ctl, atl := 100.0/42, 100.0/7
+	if math.Abs(days[0].ChronicLoad-ctl) > 1e-9 || math.Abs(days[0].AcuteLoad-atl) > 1e-9 || days[0].Balance != 0 {
+		t.Errorf("first day incorrect, got %+v", days[0])
+	}
+
+	if math.Abs(days[1].Balance-(ctl-atl)) > 1e-9 {
+		t.Errorf("balance should be the previous day's, got %v", days[1].Balance)
+	}
+
+	ctl -= ctl / 42
+	if math.Abs(days[1].ChronicLoad-ctl) > 1e-9 {
+		t.Errorf("rest day should decay, got %v", days[1].ChronicLoad)
+	}
+
+	// options
+	days = TrainingLoad(activities, TrainingLoadOptions{
+		ChronicTimeConstant: 2,
+		AcuteTimeConstant:   1,
+		Stress:              func(a *ActivitySummary) float64 { return 10 },
+		InitialChronicLoad:  20,
+		End:                 start.AddDate(0, 0, 4).Add(time.Hour),
+	})
+
+	if len(days) != 5 {
+		t.Fatalf("should fill to the end, got %d days", len(days))
+	}
+
+	if days[0].Balance != 20 || days[0].ChronicLoad != 20 || days[0].AcuteLoad != 20 {
+		t.Errorf("first day incorrect, got %+v", days[0])
+	}
+
+	if days[4].Stress != 0 || days[4].AcuteLoad != 0 || days[4].ChronicLoad == 0 {
+		t.Errorf("last day incorrect, got %+v", days[4])
+	}
+
+	if days := TrainingLoad(nil); len(days) != 0 {
+		t.Errorf("should have no days, got %d", len(days))
+	}
+}
+
+func TestTrainingStress(t *testing.T) {
+	var activity ActivitySummary
+	json.Unmarshal([]byte(`{"moving_time":3600,"kilojoules":720,"weighted_average_watts":250,"suffer_score":82}`), &activity)
+
+	if s := SufferScoreStress(&activity); s != 82 {
+		t.Errorf("suffer score stress incorrect, got %v", s)
+	}
+
+	if s := PowerStress(250)(&activity); math.Abs(s-100) > 1e-9 {
+		t.Errorf("power stress incorrect, got %v", s)
+	}
+
+	// from kilojoules, 200 watts average
+	activity.WeightedAveragePower = 0
+	if s := PowerStress(250)(&activity); math.Abs(s-64) > 1e-9 {
+		t.Errorf("power stress incorrect, got %v", s)
+	}
+
+	if s := PowerStress(0)(&activity); s != 0 {
+		t.Errorf("power stress should be 0 without ftp, got %v", s)
+	}
+}
+
+func TestStreamSetTrainingStressScore(t *testing.T) {
+	set := &StreamSet{
+		Time:  &IntegerStream{Data: make([]int, 0)},
+		Power: &IntegerStream{Data: make([]int, 0)},
+	}
+
+	// an hour at ftp, sampled every 2 seconds
+	for i := 0; i <= 1800; i++ {
+		set.Time.Data = append(set.Time.Data, 2*i)
+		set.Power.Data = append(set.Power.Data, 250)
+	}
+
+	tss, err := set.TrainingStressScore(250)
+	if err != nil {
+		t.Fatalf("tss error: %v", err)
+	}
+
+	if math.Abs(tss-100) > 1e-9 {
+		t.Errorf("tss incorrect, got %v", tss)
+	}
+
+	// alternating surges are normalized above the average
+	for i := range set.Power.Data {
+		set.Power.Data[i] = 0
+		if (i/30)%2 == 0 {
+			set.Power.Data[i] = 500
+		}
+	}
+
+	tss, _ = set.TrainingStressScore(250)
+	if tss <= 100 {
+		t.Errorf("tss should be above the average, got %v", tss)
+	}
+
+	// a 20 minute stop recorded as one gap is skipped
+	for i := range set.Power.Data {
+		set.Power.Data[i] = 250
+		if i > 900 {
+			set.Time.Data[i] += 1200
+		}
+	}
+
+	tss, _ = set.TrainingStressScore(250)
+	if math.Abs(tss-100*3598.0/3600) > 1e-9 {
+		t.Errorf("tss should skip the gap, got %v", tss)
+	}
+
+	// as are stopped samples
+	set.Moving = &BooleanStream{Data: make([]bool, len(set.Time.Data))}
+	for i := range set.Moving.Data {
+		set.Moving.Data[i] = i > 900
+	}
+
+	tss, _ = set.TrainingStressScore(250)
+	if math.Abs(tss-100*1798.0/3600) > 1e-9 {
+		t.Errorf("tss should skip stopped samples, got %v", tss)
+	}
+
+	// errors
+	if _, err := set.TrainingStressScore(0); err == nil {
+		t.Error("should require ftp")
+	}
+
+	set.Power = nil
+	if _, err := set.TrainingStressScore(250); err == nil {
+		t.Error("should require power")
+	}
+}
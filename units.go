@@ -0,0 +1,207 @@
+package strava
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	metersPerKilometer = 1000.0
+	metersPerMile      = 1609.344
+	metersPerYard      = 0.9144
+	metersPerFoot      = 0.3048
+)
+
+// Distance is in meters, eg. ActivitySummary.Distance.
+//
+// Distance and the other quantities wrap the raw values of the API with conversions
+// and formatting in the athlete's AthleteDetailed.MeasurementPreference.
+// Any preference other than MeasurementPreferences.Feet is formatted in metric, eg.
+//
+//	strava.Distance(activity.Distance).Format(athlete.MeasurementPreference) // "5.2 mi"
+//	strava.Speed(activity.AverageSpeed).Pace().Format(athlete.MeasurementPreference) // "7:45 /mi"
+//	strava.Duration(activity.MovingTime).String() // "1h23m"
+type Distance float64
+
+// Elevation is in meters, eg. ActivitySummary.TotalElevationGain.
+type Elevation float64
+
+// Speed is in meters per second, eg. ActivitySummary.AverageSpeed.
+type Speed float64
+
+// Pace is in seconds per meter, see Speed.Pace.
+type Pace float64
+
+// Duration is in seconds, eg. ActivitySummary.MovingTime.
+type Duration int
+
+func (d Distance) Kilometers() float64 {
+	return float64(d) / metersPerKilometer
+}
+
+func (d Distance) Miles() float64 {
+	return float64(d) / metersPerMile
+}
+
+func (d Distance) Yards() float64 {
+	return float64(d) / metersPerYard
+}
+
+// Format returns the distance in kilometers or miles, eg. "5.2 mi".
+func (d Distance) Format(preference string) string {
+	if imperial(preference) {
+		return fmt.Sprintf("%.1f mi", d.Miles())
+	}
+
+	return fmt.Sprintf("%.1f km", d.Kilometers())
+}
+
+// SwimFormat returns the distance in meters or yards, eg. "1500 m".
+func (d Distance) SwimFormat(preference string) string {
+	if imperial(preference) {
+		return fmt.Sprintf("%.0f yd", d.Yards())
+	}
+
+	return fmt.Sprintf("%.0f m", float64(d))
+}
+
+func (d Distance) String() string {
+	return d.Format(MeasurementPreferences.Meters)
+}
+
+func (e Elevation) Feet() float64 {
+	return float64(e) / metersPerFoot
+}
+
+// Format returns the elevation in meters or feet, eg. "404 ft".
+func (e Elevation) Format(preference string) string {
+	if imperial(preference) {
+		return fmt.Sprintf("%.0f ft", e.Feet())
+	}
+
+	return fmt.Sprintf("%.0f m", float64(e))
+}
+
+func (e Elevation) String() string {
+	return e.Format(MeasurementPreferences.Meters)
+}
+
+func (s Speed) KilometersPerHour() float64 {
+	return float64(s) * 3600 / metersPerKilometer
+}
+
+func (s Speed) MilesPerHour() float64 {
+	return float64(s) * 3600 / metersPerMile
+}
+
+// Pace returns the time per distance at the speed, 0 if not moving.
+func (s Speed) Pace() Pace {
+	if s <= 0 {
+		return 0
+	}
+
+	return Pace(1 / float64(s))
+}
+
+// Format returns the speed in kilometers or miles per hour, eg. "25.3 km/h".
+func (s Speed) Format(preference string) string {
+	if imperial(preference) {
+		return fmt.Sprintf("%.1f mi/h", s.MilesPerHour())
+	}
+
+	return fmt.Sprintf("%.1f km/h", s.KilometersPerHour())
+}
+
+func (s Speed) String() string {
+	return s.Format(MeasurementPreferences.Meters)
+}
+
+func (p Pace) PerKilometer() time.Duration {
+	return p.per(metersPerKilometer)
+}
+
+func (p Pace) PerMile() time.Duration {
+	return p.per(metersPerMile)
+}
+
+func (p Pace) Per100Meters() time.Duration {
+	return p.per(100)
+}
+
+func (p Pace) Per100Yards() time.Duration {
+	return p.per(100 * metersPerYard)
+}
+
+// Format returns the time per kilometer or mile, eg. "7:45 /mi", or "-" if not moving.
+func (p Pace) Format(preference string) string {
+	if p <= 0 {
+		return "-"
+	}
+
+	if imperial(preference) {
+		return formatPace(p.PerMile()) + " /mi"
+	}
+
+	return formatPace(p.PerKilometer()) + " /km"
+}
+
+// SwimFormat returns the time per 100 meters or yards, eg. "1:45 /100m", or "-" if not moving.
+func (p Pace) SwimFormat(preference string) string {
+	if p <= 0 {
+		return "-"
+	}
+
+	if imperial(preference) {
+		return formatPace(p.Per100Yards()) + " /100yd"
+	}
+
+	return formatPace(p.Per100Meters()) + " /100m"
+}
+
+func (p Pace) String() string {
+	return p.Format(MeasurementPreferences.Meters)
+}
+
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d) * time.Second
+}
+
+// String returns the hours and minutes, or minutes and seconds if under an hour, eg. "1h23m" or "4m05s".
+func (d Duration) String() string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+
+	if d >= 3600 {
+		return fmt.Sprintf("%s%dh%02dm", sign, d/3600, d%3600/60)
+	}
+
+	if d >= 60 {
+		return fmt.Sprintf("%s%dm%02ds", sign, d/60, d%60)
+	}
+
+	return fmt.Sprintf("%s%ds", sign, d)
+}
+
+/*********************************************************/
+
+// imperial is true for an AthleteDetailed.MeasurementPreference of feet.
+func imperial(preference string) bool {
+	return preference == MeasurementPreferences.Feet
+}
+
+func (p Pace) per(meters float64) time.Duration {
+	return time.Duration(math.Round(float64(p)*meters)) * time.Second
+}
+
+// formatPace returns minutes and seconds, or hours, minutes and seconds, eg. "7:45" or "1:02:03".
+func formatPace(d time.Duration) string {
+	seconds := int(d / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+	}
+
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
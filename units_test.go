@@ -0,0 +1,113 @@
+package strava
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDistance(t *testing.T) {
+	d := Distance(8368.6)
+
+	if s := d.Format("feet"); s != "5.2 mi" {
+		t.Errorf("distance incorrect, got %v", s)
+	}
+
+	if s := d.Format("meters"); s != "8.4 km" {
+		t.Errorf("distance incorrect, got %v", s)
+	}
+
+	if s := d.String(); s != "8.4 km" {
+		t.Errorf("distance should default to metric, got %v", s)
+	}
+
+	if s := Distance(1500).SwimFormat("meters"); s != "1500 m" {
+		t.Errorf("swim distance incorrect, got %v", s)
+	}
+
+	if s := Distance(1500).SwimFormat("feet"); s != "1640 yd" {
+		t.Errorf("swim distance incorrect, got %v", s)
+	}
+}
+
+func TestElevation(t *testing.T) {
+	e := Elevation(123.2)
+
+	if s := e.Format("feet"); s != "404 ft" {
+		t.Errorf("elevation incorrect, got %v", s)
+	}
+
+	if s := e.Format(""); s != "123 m" {
+		t.Errorf("elevation incorrect, got %v", s)
+	}
+}
+
+func TestSpeed(t *testing.T) {
+	s := Speed(7.313)
+
+	if f := s.Format("meters"); f != "26.3 km/h" {
+		t.Errorf("speed incorrect, got %v", f)
+	}
+
+	if f := s.Format("feet"); f != "16.4 mi/h" {
+		t.Errorf("speed incorrect, got %v", f)
+	}
+
+	if p := Speed(0).Pace(); p != 0 {
+		t.Errorf("pace should be 0 when not moving, got %v", p)
+	}
+}
+
+func TestPace(t *testing.T) {
+	// 7:45 per mile
+	p := Speed(1609.344 / 465).Pace()
+
+	if p.PerMile() != 465*time.Second {
+		t.Errorf("pace incorrect, got %v", p.PerMile())
+	}
+
+	if s := p.Format("feet"); s != "7:45 /mi" {
+		t.Errorf("pace incorrect, got %v", s)
+	}
+
+	if s := p.Format("meters"); s != "4:49 /km" {
+		t.Errorf("pace incorrect, got %v", s)
+	}
+
+	// 1:45 per 100 meters
+	p = Speed(100.0 / 105).Pace()
+	if s := p.SwimFormat("meters"); s != "1:45 /100m" {
+		t.Errorf("swim pace incorrect, got %v", s)
+	}
+
+	if s := p.SwimFormat("feet"); s != "1:36 /100yd" {
+		t.Errorf("swim pace incorrect, got %v", s)
+	}
+
+	if s := Pace(4).String(); s != "1:06:40 /km" {
+		t.Errorf("pace incorrect, got %v", s)
+	}
+
+	if s := Speed(0).Pace().Format("meters"); s != "-" {
+		t.Errorf("pace should be a dash when not moving, got %v", s)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := map[Duration]string{
+		4980:  "1h23m",
+		36000: "10h00m",
+		245:   "4m05s",
+		42:    "42s",
+		-245:  "-4m05s",
+	}
+
+	for d, expected := range cases {
+		if s := d.String(); s != expected {
+			t.Errorf("duration %d incorrect, got %v", d, s)
+		}
+	}
+
+	if d := Duration(90).Duration(); d != 90*time.Second {
+		t.Errorf("duration incorrect, got %v", d)
+	}
+}
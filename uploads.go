@@ -177,7 +177,7 @@ func (c *UploadsCreateCall) Do() (*UploadSummary, error) {
 
 	writer.Close() // so it finishes writing everything to the body buffer
 
-	req, err := http.NewRequest("POST", basePath+"/uploads", body)
+	req, err := http.NewRequest("POST", c.service.client.url("/uploads"), body)
 	req.Header.Add("Content-Type", "multipart/form-data; boundary="+writer.Boundary())
 
 	data, err := c.service.client.runRequestWithErrorHandler(req, errorHandler)
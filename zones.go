@@ -1,5 +1,9 @@
 package strava
 
+import (
+	"errors"
+)
+
 type ZonesSummary struct {
 	Score       int           `json:"score"`
 	Buckets     []*ZoneBucket `json:"distribution_buckets"`
@@ -14,3 +18,117 @@ type ZoneBucket struct {
 	Max  int `json:"max"`
 	Time int `json:"time"`
 }
+
+// AthleteZones are the athlete's configured zones, see CurrentAthleteService.Zones.
+// Power is nil if the athlete has not set their FTP.
+type AthleteZones struct {
+	HeartRate *HeartRateZones `json:"heart_rate"`
+	Power     *PowerZones     `json:"power"`
+}
+
+type HeartRateZones struct {
+	CustomZones bool  `json:"custom_zones"`
+	Zones       Zones `json:"zones"`
+}
+
+type PowerZones struct {
+	Zones Zones `json:"zones"`
+}
+
+// Zones are heart rate or power ranges in increasing order. Each zone includes its Min
+// but not its Max, a Max of -1 means there is no upper limit.
+type Zones []*ZoneRange
+
+type ZoneRange struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// Zones returns the ranges of the summary's buckets, so the zones
+// of an activity can be used to classify other values.
+func (z *ZonesSummary) Zones() Zones {
+	zones := make(Zones, 0, len(z.Buckets))
+	for _, b := range z.Buckets {
+		zones = append(zones, &ZoneRange{b.Min, b.Max})
+	}
+
+	return zones
+}
+
+// Zone returns the index of the zone containing the value, or -1 if none do.
+func (z Zones) Zone(value float64) int {
+	for i, r := range z {
+		if r.contains(value) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// HeartRateDistribution returns the time in seconds spent in each zone using the
+// HeartRate and Time streams. The result is in the format of ActivitiesService.ListZones.
+func (z Zones) HeartRateDistribution(streams *StreamSet) (*ZonesSummary, error) {
+	if streams.HeartRate == nil {
+		return nil, errors.New("heartrate stream required")
+	}
+
+	return z.distribution("heartrate", streams, streams.HeartRate)
+}
+
+// PowerDistribution returns the time in seconds spent in each zone using the
+// Power and Time streams. The result is in the format of ActivitiesService.ListZones.
+func (z Zones) PowerDistribution(streams *StreamSet) (*ZonesSummary, error) {
+	if streams.Power == nil {
+		return nil, errors.New("power stream required")
+	}
+
+	return z.distribution("power", streams, streams.Power)
+}
+
+/*********************************************************/
+
+// distribution adds the time between each sample and the previous
+// to the zone of the value at the sample.
+func (z Zones) distribution(zoneType string, streams *StreamSet, values *IntegerStream) (*ZonesSummary, error) {
+	if streams.Time == nil || len(values.Data) != len(streams.Time.Data) {
+		return nil, errors.New("time stream required")
+	}
+
+	summary := &ZonesSummary{
+		Type:        zoneType,
+		SensorBased: true,
+		Buckets:     make([]*ZoneBucket, 0, len(z)),
+	}
+
+	for _, r := range z {
+		summary.Buckets = append(summary.Buckets, &ZoneBucket{Min: r.Min, Max: r.Max})
+	}
+
+	samples := streams.validTimeIndexes()
+	for j := 1; j < len(samples); j++ {
+		i := samples[j]
+		if !values.valid(i) {
+			continue
+		}
+
+		if zone := z.Zone(float64(values.Data[i])); zone >= 0 {
+			summary.Buckets[zone].Time += streams.Time.Data[i] - streams.Time.Data[samples[j-1]]
+		}
+	}
+
+	return summary, nil
+}
+
+func (r *ZoneRange) contains(value float64) bool {
+	if value < float64(r.Min) {
+		return false
+	}
+
+	// eg. the 0 watts zone of activity power zones
+	if r.Min == r.Max {
+		return value == float64(r.Min)
+	}
+
+	return r.Max == -1 || value < float64(r.Max)
+}
@@ -0,0 +1,84 @@
+package strava
+
+import (
+	"testing"
+)
+
+func TestZonesZone(t *testing.T) {
+	zones := Zones{{0, 115}, {115, 152}, {152, 171}, {171, 190}, {190, -1}}
+
+	tests := map[float64]int{
+		-5:  -1,
+		0:   0,
+		114: 0,
+		115: 1,
+		170: 2,
+		189: 3,
+		190: 4,
+		250: 4,
+	}
+
+	for value, expected := range tests {
+		if z := zones.Zone(value); z != expected {
+			t.Errorf("zone of %v incorrect, got %d", value, z)
+		}
+	}
+
+	// activity power zones start with a zone for not pedaling
+	zones = (&ZonesSummary{Buckets: []*ZoneBucket{{0, 0, 0}, {0, 50, 0}, {50, -1, 0}}}).Zones()
+	if z := zones.Zone(0); z != 0 {
+		t.Errorf("zone of 0 incorrect, got %d", z)
+	}
+
+	if z := zones.Zone(1); z != 1 {
+		t.Errorf("zone of 1 incorrect, got %d", z)
+	}
+}
+
+func TestZonesDistribution(t *testing.T) {
+	set := newTestLapStreamSet() // heart rate is 100 to 180
+	set.Power = &IntegerStream{Data: make([]int, len(set.Time.Data))}
+
+	zones := Zones{{0, 120}, {120, 150}, {150, -1}}
+
+	summary, err := zones.HeartRateDistribution(set)
+	if err != nil {
+		t.Fatalf("distribution error: %v", err)
+	}
+
+	if summary.Type != "heartrate" || len(summary.Buckets) != 3 {
+		t.Fatalf("distribution incorrect, got %v", summary)
+	}
+
+	// the first sample has no time
+	expected := []int{19, 30, 31}
+	for i, b := range summary.Buckets {
+		if b.Time != expected[i] {
+			t.Errorf("bucket %d time incorrect, got %d", i, b.Time)
+		}
+
+		if b.Min != zones[i].Min || b.Max != zones[i].Max {
+			t.Errorf("bucket %d range incorrect, got %d to %d", i, b.Min, b.Max)
+		}
+	}
+
+	summary, err = zones.PowerDistribution(set)
+	if err != nil {
+		t.Fatalf("distribution error: %v", err)
+	}
+
+	if summary.Type != "power" || summary.Buckets[0].Time != 80 {
+		t.Errorf("distribution incorrect, got %v", summary.Buckets[0])
+	}
+
+	// errors
+	set.Power = nil
+	if _, err := zones.PowerDistribution(set); err == nil {
+		t.Error("should return error without power stream")
+	}
+
+	set.Time = nil
+	if _, err := zones.HeartRateDistribution(set); err == nil {
+		t.Error("should return error without time stream")
+	}
+}